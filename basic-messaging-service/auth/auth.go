@@ -0,0 +1,226 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+)
+
+// Metadata keys calling services authenticate with, a key is sent as x-api-key
+// and a signed token as authorization: Bearer <token>
+const (
+	APIKeyMetadataKey        = "x-api-key"
+	AuthorizationMetadataKey = "authorization"
+	// CallerMetadataKey => calling service of the request when authentication is disabled
+	CallerMetadataKey = "x-caller-service"
+)
+
+// AnonymousClient => client of requests without caller metadata, when authentication is disabled
+const AnonymousClient = "anonymous"
+
+// Service => grpc service whose rpcs need permissions, other services (eg: reflection) only need
+// an authenticated client, except health checks which are open to probes
+const (
+	Service       = "Notification"
+	healthService = "grpc.health.v1.Health"
+)
+
+// Permission groups, admin may call every rpc
+const (
+	PermissionSend   = "send"
+	PermissionRead   = "read"
+	PermissionManage = "manage"
+	PermissionAdmin  = "admin"
+)
+
+// groups => rpcs of every permission group (admin is not listed, it allows all of them)
+// Queue and dead letter rpcs are admin only, they can drain or replay messages of every client.
+var groups = map[string][]string{
+	PermissionSend: {"SendNotification", "AddToQueue", "AddToQueueBatch", "EnqueueStream", "StoreAttachment"},
+	PermissionRead: {"GetMessageStatus", "WatchDeliveries", "GetTemplate", "ListTemplates",
+		"GetPreferences", "ListSuppressions"},
+	PermissionManage: {"CreateTemplate", "UpdateTemplate", "AddSuppression", "RemoveSuppression", "UpdatePreferences"},
+}
+
+// adminRPCs => rpcs which are not in any permission group
+var adminRPCs = []string{"RemoveFromQueue", "ListDeadLetters", "GetDeadLetter", "ReplayDeadLetter", "PurgeDeadLetters"}
+
+// Client => authenticated calling service and the rpcs it may call
+type Client struct {
+	Name  string
+	admin bool
+	rpcs  map[string]bool
+}
+
+// Allowed => reports whether client may call the rpc
+func (c *Client) Allowed(rpc string) bool {
+	return c.admin || c.rpcs[strings.ToLower(rpc)]
+}
+
+type clientKey struct{}
+
+// WithClient => returns ctx carrying the authenticated client
+func WithClient(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext => authenticated client of the request, anonymous when there is none
+func ClientFromContext(ctx context.Context) *Client {
+	if client, ok := ctx.Value(clientKey{}).(*Client); ok {
+		return client
+	}
+	return &Client{Name: AnonymousClient}
+}
+
+// Authenticator => identifies calling services and checks their permissions
+type Authenticator struct {
+	config *configs.AuthConfig
+	// keys => sha256 of every api key and its client, keys are looked up by their hash
+	// so lookup time does not depend on how much of a key matches
+	keys    map[[sha256.Size]byte]string
+	clients map[string]*Client
+}
+
+// NewAuthenticator => returns an authenticator of the configured clients, fails on unknown permissions
+func NewAuthenticator(config *configs.AuthConfig) (*Authenticator, error) {
+	a := &Authenticator{
+		config:  config,
+		keys:    make(map[[sha256.Size]byte]string),
+		clients: make(map[string]*Client),
+	}
+
+	for client, key := range config.APIKeys {
+		a.keys[sha256.Sum256([]byte(key))] = client
+	}
+	for client, permissions := range config.Permissions {
+		c, err := newClient(client, permissions)
+		if err != nil {
+			return nil, err
+		}
+		a.clients[client] = c
+	}
+	if _, err := newClient("*", config.DefaultPermissions); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// newClient => client with the given permissions, a permission is a group or a rpc name
+func newClient(name string, permissions []string) (*Client, error) {
+	client := &Client{Name: name, rpcs: make(map[string]bool)}
+	for _, permission := range permissions {
+		permission = strings.ToLower(permission)
+		if permission == PermissionAdmin {
+			client.admin = true
+		} else if rpcs, ok := groups[permission]; ok {
+			for _, rpc := range rpcs {
+				client.rpcs[strings.ToLower(rpc)] = true
+			}
+		} else if isRPC(permission) {
+			client.rpcs[permission] = true
+		} else {
+			return nil, fmt.Errorf("unknown permission %s of client %s", permission, name)
+		}
+	}
+	return client, nil
+}
+
+// client => configured client of name, clients which are not configured get the default permissions
+func (a *Authenticator) client(name string) *Client {
+	if client, ok := a.clients[name]; ok {
+		return client
+	}
+
+	client, _ := newClient(name, a.config.DefaultPermissions)
+	return client
+}
+
+// Authenticate => client of the request, from its api key or signed token
+// Returns UNAUTHENTICATED when request has no valid credentials. When authentication is
+// disabled every request is allowed, as the caller it names in its metadata.
+func (a *Authenticator) Authenticate(ctx context.Context) (*Client, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if !a.config.Enabled() {
+		name := AnonymousClient
+		if values := md.Get(CallerMetadataKey); len(values) > 0 && values[0] != "" {
+			name = values[0]
+		}
+		return &Client{Name: name, admin: true}, nil
+	}
+
+	if values := md.Get(APIKeyMetadataKey); len(values) > 0 {
+		if name, ok := a.keys[sha256.Sum256([]byte(values[0]))]; ok {
+			return a.client(name), nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid api key")
+	}
+
+	if values := md.Get(AuthorizationMetadataKey); len(values) > 0 {
+		token := strings.TrimSpace(values[0])
+		if len(token) < 7 || !strings.EqualFold(token[:7], "bearer ") {
+			return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+		}
+
+		name, err := verifyToken(a.config.TokenSecret, strings.TrimSpace(token[7:]))
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+		}
+		return a.client(strings.ToLower(name)), nil
+	}
+
+	return nil, status.Errorf(codes.Unauthenticated, "%s or %s is required", APIKeyMetadataKey, AuthorizationMetadataKey)
+}
+
+// Authorize => authenticates request of the full grpc method (eg: /Notification/AddToQueue)
+// and checks client may call it, returns ctx carrying the client
+func (a *Authenticator) Authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	service, rpc := splitMethod(fullMethod)
+	if service == healthService {
+		return ctx, nil
+	}
+
+	client, err := a.Authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if service == Service && !client.Allowed(rpc) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s is not allowed to call %s", client.Name, rpc)
+	}
+
+	return WithClient(ctx, client), nil
+}
+
+// splitMethod => service and rpc of a full grpc method
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i != -1 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "", fullMethod
+}
+
+// isRPC => reports whether name (lower cased) is a rpc of any permission group or an admin only rpc
+func isRPC(name string) bool {
+	for _, rpc := range adminRPCs {
+		if strings.ToLower(rpc) == name {
+			return true
+		}
+	}
+	for _, rpcs := range groups {
+		for _, rpc := range rpcs {
+			if strings.ToLower(rpc) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+)
+
+func TestAuthorize(t *testing.T) {
+	a, err := NewAuthenticator(&configs.AuthConfig{
+		APIKeys:            map[string]string{"rest-api": "rest-key", "ops": "ops-key"},
+		TokenSecret:        []byte("secret"),
+		Permissions:        map[string][]string{"ops": {"admin"}, "reports": {"read"}},
+		DefaultPermissions: []string{"send"},
+	})
+	if err != nil {
+		t.Fatalf("NewAuthenticator() error = %v", err)
+	}
+	token := newToken("secret", `{"alg":"HS256"}`, fmt.Sprintf(`{"sub":"Reports","exp":%d}`, time.Now().Unix()+60))
+
+	tests := []struct {
+		name   string
+		md     metadata.MD
+		method string
+		client string
+		code   codes.Code
+	}{
+		{"api key", metadata.Pairs(APIKeyMetadataKey, "rest-key"), "/Notification/SendNotification", "rest-api", codes.OK},
+		{"admin rpc", metadata.Pairs(APIKeyMetadataKey, "ops-key"), "/Notification/ReplayDeadLetter", "ops", codes.OK},
+		{"bearer token", metadata.Pairs(AuthorizationMetadataKey, "Bearer "+token), "/Notification/GetMessageStatus",
+			"reports", codes.OK},
+		{"not permitted", metadata.Pairs(APIKeyMetadataKey, "rest-key"), "/Notification/ReplayDeadLetter", "", codes.PermissionDenied},
+		{"token not permitted", metadata.Pairs(AuthorizationMetadataKey, "Bearer "+token), "/Notification/AddToQueue",
+			"", codes.PermissionDenied},
+		{"invalid api key", metadata.Pairs(APIKeyMetadataKey, "rest"), "/Notification/SendNotification", "", codes.Unauthenticated},
+		{"not a bearer token", metadata.Pairs(AuthorizationMetadataKey, token), "/Notification/SendNotification",
+			"", codes.Unauthenticated},
+		{"invalid token", metadata.Pairs(AuthorizationMetadataKey, "Bearer x.y.z"), "/Notification/SendNotification",
+			"", codes.Unauthenticated},
+		{"no credentials", metadata.MD{}, "/Notification/SendNotification", "", codes.Unauthenticated},
+		{"health checks are open", metadata.MD{}, "/grpc.health.v1.Health/Check", "", codes.OK},
+		{"other services need a client", metadata.Pairs(APIKeyMetadataKey, "rest-key"),
+			"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo", "rest-api", codes.OK},
+	}
+
+	for _, test := range tests {
+		ctx, err := a.Authorize(metadata.NewIncomingContext(context.Background(), test.md), test.method)
+		if status.Code(err) != test.code {
+			t.Errorf("%s: Authorize() error = %v, want %v", test.name, err, test.code)
+			continue
+		}
+		if test.client != "" && ClientFromContext(ctx).Name != test.client {
+			t.Errorf("%s: client = %s, want %s", test.name, ClientFromContext(ctx).Name, test.client)
+		}
+	}
+}
+
+func TestAuthorizeDisabled(t *testing.T) {
+	a, err := NewAuthenticator(&configs.AuthConfig{DefaultPermissions: []string{"send"}})
+	if err != nil {
+		t.Fatalf("NewAuthenticator() error = %v", err)
+	}
+
+	md := metadata.Pairs(CallerMetadataKey, "rest-api")
+	ctx, err := a.Authorize(metadata.NewIncomingContext(context.Background(), md), "/Notification/PurgeDeadLetters")
+	if err != nil || ClientFromContext(ctx).Name != "rest-api" {
+		t.Errorf("Authorize() = %v, %v, want rest-api allowed", ClientFromContext(ctx).Name, err)
+	}
+
+	ctx, err = a.Authorize(context.Background(), "/Notification/SendNotification")
+	if err != nil || ClientFromContext(ctx).Name != AnonymousClient {
+		t.Errorf("Authorize() = %v, %v, want anonymous allowed", ClientFromContext(ctx).Name, err)
+	}
+}
+
+func TestNewAuthenticatorUnknownPermission(t *testing.T) {
+	_, err := NewAuthenticator(&configs.AuthConfig{Permissions: map[string][]string{"ops": {"everything"}}})
+	if err == nil {
+		t.Error("NewAuthenticator() accepted an unknown permission")
+	}
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newToken => token of header and claims (json) signed with secret
+func newToken(secret, header, claims string) string {
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyToken(t *testing.T) {
+	secret := "secret"
+	header := `{"alg":"HS256","typ":"JWT"}`
+	now := time.Now().Unix()
+	valid := newToken(secret, header, fmt.Sprintf(`{"sub":"rest-api","exp":%d}`, now+60))
+
+	tests := []struct {
+		name   string
+		secret string
+		token  string
+		err    string
+	}{
+		{"valid", secret, valid, ""},
+		{"valid after not before", secret,
+			newToken(secret, header, fmt.Sprintf(`{"sub":"rest-api","exp":%d,"nbf":%d}`, now+60, now-60)), ""},
+		{"tokens not accepted", "", valid, "signed tokens are not accepted"},
+		{"wrong secret", "other", valid, "signature does not match"},
+		{"tampered claims", secret, strings.Replace(valid, strings.Split(valid, ".")[1],
+			base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"ops","exp":%d}`, now+60))), 1),
+			"signature does not match"},
+		{"expired", secret, newToken(secret, header, fmt.Sprintf(`{"sub":"rest-api","exp":%d}`, now-1)), "token has expired"},
+		{"no expiry", secret, newToken(secret, header, `{"sub":"rest-api"}`), "token has expired"},
+		{"not valid yet", secret,
+			newToken(secret, header, fmt.Sprintf(`{"sub":"rest-api","exp":%d,"nbf":%d}`, now+60, now+30)),
+			"token is not valid yet"},
+		{"no subject", secret, newToken(secret, header, fmt.Sprintf(`{"exp":%d}`, now+60)), "token has no subject"},
+		{"alg none", secret, newToken(secret, `{"alg":"none"}`, fmt.Sprintf(`{"sub":"rest-api","exp":%d}`, now+60)),
+			"token must be signed with HS256"},
+		{"malformed", secret, "not.a-token", "malformed token"},
+		{"malformed header", secret, "e30x." + strings.SplitN(valid, ".", 2)[1], "malformed token"},
+	}
+
+	for _, test := range tests {
+		client, err := verifyToken([]byte(test.secret), test.token)
+		if test.err == "" {
+			if err != nil || client != "rest-api" {
+				t.Errorf("%s: verifyToken() = %q, %v, want rest-api", test.name, client, err)
+			}
+		} else if err == nil || err.Error() != test.err {
+			t.Errorf("%s: verifyToken() error = %v, want %q", test.name, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/logging"
+)
+
+// Reloader => keeps the certificate (and client CAs) of the server loaded from files,
+// replacing them when the files change so rotated certificates are used without a restart
+type Reloader struct {
+	config *configs.TLSConfig
+	log    *logging.LogWrapper
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  []time.Time
+}
+
+// NewReloader => returns a reloader with the certificate files loaded, fails when they are invalid
+func NewReloader(config *configs.TLSConfig, l *logging.LogWrapper) (*Reloader, error) {
+	r := &Reloader{config: config, log: l}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// files => files loaded by the reloader, client CA file is only given for mutual TLS
+func (r *Reloader) files() []string {
+	files := []string{r.config.CertFile, r.config.KeyFile}
+	if r.config.ClientCAFile != "" {
+		files = append(files, r.config.ClientCAFile)
+	}
+	return files
+}
+
+// load => reads all the files, nothing is replaced when one of them is invalid
+// Failed files are not read again until they change, eg: once key of a replaced certificate is written.
+func (r *Reloader) load() error {
+	modTimes, err := modTimes(r.files())
+	if err != nil {
+		return err
+	}
+	cert, clientCAs, err := r.read()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.modTimes = modTimes
+	if err != nil {
+		return err
+	}
+	r.cert = cert
+	r.clientCAs = clientCAs
+	return nil
+}
+
+func (r *Reloader) read() (*tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to load certificate: %v", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.config.ClientCAFile != "" {
+		if clientCAs, err = loadPool(r.config.ClientCAFile); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &cert, clientCAs, nil
+}
+
+// changed => reports whether any of the files was modified since it was last loaded
+func (r *Reloader) changed() (bool, error) {
+	modTimes, err := modTimes(r.files())
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[i]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Watch => checks the files for changes every interval until ctx is done
+// A failed reload keeps the current certificate.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := r.changed()
+		if err != nil {
+			r.log.Error("Unable to check certificate files: %v", err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		if err := r.load(); err != nil {
+			r.log.Error("Unable to reload certificates, keeping current ones: %v", err)
+			continue
+		}
+		r.log.Info("Reloaded certificates from %s", r.config.CertFile)
+	}
+}
+
+// ServerConfig => tls config of the grpc server, every handshake uses the latest loaded certificates
+// Clients have to present a certificate signed by one of the client CAs when client CA file is given.
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				NextProtos:   []string{"h2"},
+			}
+			if r.clientCAs != nil {
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+				config.ClientCAs = r.clientCAs
+			}
+			return config, nil
+		},
+	}
+}
+
+// loadPool => reads PEM encoded certificates of file into a pool
+func loadPool(file string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("no certificates found in " + file)
+	}
+	return pool, nil
+}
+
+func modTimes(files []string) ([]time.Time, error) {
+	modTimes := make([]time.Time, len(files))
+	for i, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
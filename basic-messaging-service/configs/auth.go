@@ -0,0 +1,98 @@
+package configs
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AuthConfig => how calling services are authenticated, what they may call and how much they may send
+// Clients are identified by an API key or by a HS256 signed token (JWT) whose subject is the client name.
+// Authentication is enabled when any API key or the token secret is set.
+// Permissions are permission groups (send, read, manage, admin) or rpc names, clients which are not
+// listed get DefaultPermissions. DailyQuotas are messages a client may send (or queue) per day (UTC),
+// clients which are not listed get DefaultDailyQuota, 0 is no quota.
+type AuthConfig struct {
+	APIKeys            map[string]string
+	TokenSecret        []byte
+	Permissions        map[string][]string
+	DefaultPermissions []string
+	DailyQuotas        map[string]int
+	DefaultDailyQuota  int
+}
+
+// Enabled => reports whether callers have to authenticate
+func (c *AuthConfig) Enabled() bool {
+	return len(c.APIKeys) > 0 || len(c.TokenSecret) > 0
+}
+
+// NewAuthConfig returns authentication configurations instance
+// AUTH_API_KEYS => client=key list, eg: rest-api=4f9c...,ops=a17e...
+// AUTH_TOKEN_SECRET => secret of HS256 signed tokens
+// AUTH_PERMISSIONS => client=permission|permission list, * is used for clients not in the list (default send|read)
+// AUTH_DAILY_QUOTAS => client=messages list, * is used for clients not in the list
+func NewAuthConfig() *AuthConfig {
+	permissions := make(map[string][]string)
+	for client, value := range parseNamedValues(getEnv("AUTH_PERMISSIONS", "")) {
+		permissions[client] = splitPermissions(value)
+	}
+	defaultPermissions, ok := permissions["*"]
+	if !ok {
+		defaultPermissions = []string{"send", "read"}
+	}
+	delete(permissions, "*")
+
+	quotas := make(map[string]int)
+	for client, value := range parseNamedValues(getEnv("AUTH_DAILY_QUOTAS", "")) {
+		if quota, err := strconv.Atoi(value); err == nil && quota >= 0 {
+			quotas[client] = quota
+		}
+	}
+	defaultQuota := quotas["*"]
+	delete(quotas, "*")
+
+	return &AuthConfig{
+		APIKeys:            parseNamedValues(getEnv("AUTH_API_KEYS", "")),
+		TokenSecret:        []byte(getEnv("AUTH_TOKEN_SECRET", "")),
+		Permissions:        permissions,
+		DefaultPermissions: defaultPermissions,
+		DailyQuotas:        quotas,
+		DefaultDailyQuota:  defaultQuota,
+	}
+}
+
+// DailyQuota => messages client may send per day, 0 when client has no quota
+func (c *AuthConfig) DailyQuota(client string) int {
+	if quota, ok := c.DailyQuotas[client]; ok {
+		return quota
+	}
+	return c.DefaultDailyQuota
+}
+
+// parseNamedValues => parses name=value list, names are lower cased, entries without a name or value are skipped
+func parseNamedValues(value string) map[string]string {
+	values := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		if value := strings.TrimSpace(parts[1]); name != "" && value != "" {
+			values[name] = value
+		}
+	}
+
+	return values
+}
+
+// splitPermissions => splits permission|permission, permissions are lower cased
+func splitPermissions(value string) []string {
+	var permissions []string
+	for _, permission := range strings.Split(value, "|") {
+		if permission = strings.ToLower(strings.TrimSpace(permission)); permission != "" {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
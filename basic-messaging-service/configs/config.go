@@ -1,27 +1,209 @@
 package configs
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // SendGridConfig => holds all the sendgrid required configurations
+// WebhookVerificationKey is the public key of signed event webhook, events are rejected without it.
+// Events signed more than WebhookTolerance ago are rejected as replays.
 type SendGridConfig struct {
-	APIKey string
+	APIKey                 string
+	WebhookVerificationKey string
+	WebhookTolerance       time.Duration
+}
+
+// SMTPConfig => holds all the smtp relay required configurations
+type SMTPConfig struct {
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	Encryption string // starttls, tls (implicit) or none
+	Auth       string // plain, login or none
+}
+
+// Validate => returns an error when encryption or auth is unknown, so mail is not sent
+// without encryption or authentication because of a typo
+func (c *SMTPConfig) Validate() error {
+	switch c.Encryption {
+	case "none", "starttls", "tls":
+	default:
+		return fmt.Errorf("unknown SMTP_ENCRYPTION %q", c.Encryption)
+	}
+
+	switch c.Auth {
+	case "none", "plain", "login":
+	default:
+		return fmt.Errorf("unknown SMTP_AUTH %q", c.Auth)
+	}
+	return nil
+}
+
+// TwilioConfig => holds all the twilio (SMS) required configurations
+type TwilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	FromNumber string
+	APIUrl     string
 }
 
 // ServerConfig => Has all the servers configs (API keys, Client Secret, etc)
 type ServerConfig struct {
-	SendGrid  *SendGridConfig
-	RootPath  string
-	Providers *Providers
-	Redis     *RedisConfig
+	SendGrid    *SendGridConfig
+	SMTP        *SMTPConfig
+	Email       *EmailConfig
+	Attachments *AttachmentConfig
+	Unsubscribe *UnsubscribeConfig
+	Twilio      *TwilioConfig
+	RootPath    string
+	HTTP        *HTTPConfig
+	TLS         *TLSConfig
+	Auth        *AuthConfig
+	Health      *HealthConfig
+	Metrics     *MetricsConfig
+	Tracing     *TracingConfig
+	Providers   *Providers
+	Breaker     *BreakerConfig
+	Queue       *QueueConfig
+	Retry       *RetryConfig
+	Status      *StatusConfig
+	Templates   *TemplatesConfig
+	Idempotency *IdempotencyConfig
+	RateLimit   *RateLimitConfig
+	Redis       *RedisConfig
 }
 
-// Providers => Default notifications providers (Email,SMS) for server
+// Providers => Ordered notifications providers (Email,SMS) for server
+// First provider is preferred, rest are used for failover
 type Providers struct {
-	Email string
+	Email []string
+	SMS   []string
+}
+
+// BreakerConfig => circuit breaker settings, applied to every provider
+type BreakerConfig struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+// QueueConfig => priority queues scheduling policy for dispatch workers
+// strict always drains higher priorities first, weighted picks queues in ratio of their weights
+// PromoteInterval is how often scheduled messages are checked for being due
+// LeaseTimeout is how long a worker owns a message before it is returned to the queue,
+// expired leases are looked for every ReapInterval
+// BlockTimeout is how long an idle worker waits on a queue for a new message
+// MaxScheduleAhead is how far in future send_at may be, 0 means no limit
+// Backend is where messages are queued: redis lists (default), redis streams or memory (local dev, lost on restart).
+// Workers of all instances read a stream as StreamsGroup, each instance as its StreamsConsumer.
+type QueueConfig struct {
+	Backend         string
+	StreamsGroup    string
+	StreamsConsumer string
+	Scheduling      string
+	HighWeight      int
+	NormalWeight    int
+	BulkWeight      int
+	PromoteInterval time.Duration
+	LeaseTimeout    time.Duration
+	ReapInterval    time.Duration
+	BlockTimeout    time.Duration
+
+	MaxScheduleAhead time.Duration
+}
+
+// RetryConfig => retry policy for queued messages which failed to dispatch
+// Delay doubles on every attempt (BaseDelay, 2*BaseDelay, ...) up to MaxDelay, with jitter.
+// After MaxAttempts message is moved to dead letters.
+type RetryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// StatusConfig => delivery status tracking, status of a message is kept for TTL after its last change
+type StatusConfig struct {
+	TTL time.Duration
+}
+
+// TemplatesConfig => templates in SeedDir (json files) are created at startup when they do not exist,
+// existing templates are left as they are
+type TemplatesConfig struct {
+	SeedDir string
+}
+
+// IdempotencyConfig => idempotency keys are remembered for TTL after the request succeeded
+// A key is held for PendingTTL while its request is in progress, so a crashed request
+// does not block retries for the whole TTL
+type IdempotencyConfig struct {
+	TTL        time.Duration
+	PendingTTL time.Duration
+}
+
+// HTTPConfig => http server of the service, used for provider webhooks, unsubscribe links and metrics
+type HTTPConfig struct {
+	Addr string
+}
+
+// TLSConfig => transport security of the grpc server, requests are served in plain text when CertFile is not set
+// Clients must present a certificate signed by ClientCAFile when it is set (mutual TLS).
+// Files are checked for changes every ReloadInterval, so rotated certificates are used without a restart.
+type TLSConfig struct {
+	CertFile       string
+	KeyFile        string
+	ClientCAFile   string
+	ReloadInterval time.Duration
+}
+
+// Enabled => reports whether grpc server should use TLS
+func (c *TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// Validate => returns an error when TLS is partly configured, so the server is not served in plain text by mistake
+func (c *TLSConfig) Validate() error {
+	switch {
+	case c.CertFile == "" && c.ClientCAFile != "":
+		return errors.New("TLS_CLIENT_CA_FILE is set without TLS_CERT_FILE")
+	case c.CertFile == "" && c.KeyFile != "":
+		return errors.New("TLS_KEY_FILE is set without TLS_CERT_FILE")
+	case c.CertFile != "" && c.KeyFile == "":
+		return errors.New("TLS_CERT_FILE is set without TLS_KEY_FILE")
+	}
+	return nil
+}
+
+// HealthConfig => health checks of the service, redis and providers are checked every Interval
+// with Timeout for a redis ping. Worker pool is reported stalled (not live) when no worker
+// picked up work for StallTimeout, should be longer than QueueConfig.BlockTimeout and provider timeouts.
+type HealthConfig struct {
+	Interval     time.Duration
+	Timeout      time.Duration
+	StallTimeout time.Duration
+}
+
+// MetricsConfig => queue lengths and breaker states are sampled every SampleInterval
+// Metrics are served on Addr, apart from the public http server, not served when it is empty.
+type MetricsConfig struct {
+	Addr           string
+	SampleInterval time.Duration
+}
+
+// TracingConfig => where spans are exported, Exporter is none (default), stdout, file, jaeger or otlp
+// Endpoint is the jaeger collector url or otlp collector address, File is used by the file exporter.
+// SampleRatio of new traces are sampled, traces started by callers keep their sampling decision.
+type TracingConfig struct {
+	Exporter    string
+	Endpoint    string
+	File        string
+	ServiceName string
+	SampleRatio float64
 }
 
 type RedisConfig struct {
@@ -34,22 +216,73 @@ type RedisConfig struct {
 // Has all the configs/credentials needed for all the services for this server
 func NewConfig() *ServerConfig {
 	sendGrid := NewSendGridConfig()
+	smtp := NewSMTPConfig()
+	email := NewEmailConfig()
+	attachments := NewAttachmentConfig()
+	unsubscribe := NewUnsubscribeConfig()
+	twilio := NewTwilioConfig()
 	rootPath, _ := filepath.Abs("./")
+	http := NewHTTPConfig()
+	tls := NewTLSConfig()
+	auth := NewAuthConfig()
+	health := NewHealthConfig()
+	metrics := NewMetricsConfig()
+	tracing := NewTracingConfig()
 	providers := NewProviders()
+	breaker := NewBreakerConfig()
+	queue := NewQueueConfig()
+	retry := NewRetryConfig()
+	status := NewStatusConfig()
+	templates := NewTemplatesConfig()
+	idempotency := NewIdempotencyConfig()
+	rateLimit := NewRateLimitConfig()
 	redis := NewRedisConfig()
 
 	return &ServerConfig{
-		SendGrid:  sendGrid,
-		RootPath:  rootPath,
-		Providers: providers,
-		Redis:     redis,
+		SendGrid:    sendGrid,
+		SMTP:        smtp,
+		Email:       email,
+		Attachments: attachments,
+		Unsubscribe: unsubscribe,
+		Twilio:      twilio,
+		RootPath:    rootPath,
+		HTTP:        http,
+		TLS:         tls,
+		Auth:        auth,
+		Health:      health,
+		Metrics:     metrics,
+		Tracing:     tracing,
+		Providers:   providers,
+		Breaker:     breaker,
+		Queue:       queue,
+		Retry:       retry,
+		Status:      status,
+		Templates:   templates,
+		Idempotency: idempotency,
+		RateLimit:   rateLimit,
+		Redis:       redis,
 	}
 }
 
+// NewProviders returns ordered providers for each notification type
+// EMAIL_PROVIDERS is a comma separated list (eg: sendgrid,smtp), when not set
+// DEFAULT_EMAIL_PROVIDER (sendgrid or smtp) is used as the only provider
 func NewProviders() *Providers {
-	email := getEnv("DEFAULT_EMAIL_PROVIDER", "sendgrid")
+	email := getEnvList("EMAIL_PROVIDERS", getEnv("DEFAULT_EMAIL_PROVIDER", "sendgrid"))
+	sms := getEnvList("SMS_PROVIDERS", getEnv("DEFAULT_SMS_PROVIDER", "twilio"))
 	return &Providers{
 		Email: email,
+		SMS:   sms,
+	}
+}
+
+// NewBreakerConfig returns circuit breaker configurations instance
+// Breaker opens after CIRCUIT_BREAKER_FAILURES consecutive failures and
+// allows a probe request after CIRCUIT_BREAKER_OPEN_TIMEOUT
+func NewBreakerConfig() *BreakerConfig {
+	return &BreakerConfig{
+		FailureThreshold: getEnvInt("CIRCUIT_BREAKER_FAILURES", 5),
+		OpenTimeout:      getEnvDuration("CIRCUIT_BREAKER_OPEN_TIMEOUT", 30*time.Second),
 	}
 }
 
@@ -57,7 +290,142 @@ func NewProviders() *Providers {
 func NewSendGridConfig() *SendGridConfig {
 	apiKey := getEnv("SENDGRID_API_KEY", "")
 	return &SendGridConfig{
-		APIKey: apiKey,
+		APIKey:                 apiKey,
+		WebhookVerificationKey: getEnv("SENDGRID_WEBHOOK_VERIFICATION_KEY", ""),
+		WebhookTolerance:       getEnvDuration("SENDGRID_WEBHOOK_TOLERANCE", 10*time.Minute),
+	}
+}
+
+// NewSMTPConfig returns SMTP relay configurations instance
+func NewSMTPConfig() *SMTPConfig {
+	port, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil {
+		port = 587
+	}
+
+	return &SMTPConfig{
+		Host:       getEnv("SMTP_HOST", "localhost"),
+		Port:       port,
+		Username:   getEnv("SMTP_USERNAME", ""),
+		Password:   getEnv("SMTP_PASSWORD", ""),
+		Encryption: strings.ToLower(getEnv("SMTP_ENCRYPTION", "starttls")),
+		Auth:       strings.ToLower(getEnv("SMTP_AUTH", "plain")),
+	}
+}
+
+// NewHTTPConfig returns http server configurations instance
+func NewHTTPConfig() *HTTPConfig {
+	return &HTTPConfig{
+		Addr: getEnv("HTTP_ADDR", ":9093"),
+	}
+}
+
+// NewTLSConfig returns grpc server transport security configurations instance
+func NewTLSConfig() *TLSConfig {
+	return &TLSConfig{
+		CertFile:       getEnv("TLS_CERT_FILE", ""),
+		KeyFile:        getEnv("TLS_KEY_FILE", ""),
+		ClientCAFile:   getEnv("TLS_CLIENT_CA_FILE", ""),
+		ReloadInterval: getEnvDuration("TLS_RELOAD_INTERVAL", time.Minute),
+	}
+}
+
+// NewHealthConfig returns health check configurations instance
+func NewHealthConfig() *HealthConfig {
+	return &HealthConfig{
+		Interval:     getEnvDuration("HEALTH_CHECK_INTERVAL", 5*time.Second),
+		Timeout:      getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		StallTimeout: getEnvDuration("WORKER_STALL_TIMEOUT", 5*time.Minute),
+	}
+}
+
+// NewMetricsConfig returns metrics configurations instance
+func NewMetricsConfig() *MetricsConfig {
+	return &MetricsConfig{
+		Addr:           getEnv("METRICS_ADDR", ":9094"),
+		SampleInterval: getEnvDuration("METRICS_SAMPLE_INTERVAL", 15*time.Second),
+	}
+}
+
+// NewTracingConfig returns tracing configurations instance
+func NewTracingConfig() *TracingConfig {
+	return &TracingConfig{
+		Exporter:    strings.ToLower(getEnv("TRACING_EXPORTER", "none")),
+		Endpoint:    getEnv("TRACING_ENDPOINT", ""),
+		File:        getEnv("TRACING_FILE", "traces.json"),
+		ServiceName: getEnv("TRACING_SERVICE_NAME", "basic-messaging-service"),
+		SampleRatio: getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+	}
+}
+
+// NewTwilioConfig returns Twilio configurations instance
+// TWILIO_API_URL can be pointed to a local stub server for testing
+func NewTwilioConfig() *TwilioConfig {
+	return &TwilioConfig{
+		AccountSID: getEnv("TWILIO_ACCOUNT_SID", ""),
+		AuthToken:  getEnv("TWILIO_AUTH_TOKEN", ""),
+		FromNumber: getEnv("TWILIO_FROM_NUMBER", ""),
+		APIUrl:     getEnv("TWILIO_API_URL", "https://api.twilio.com"),
+	}
+}
+
+// NewQueueConfig returns queue scheduling configurations instance
+// QUEUE_SCHEDULING can be strict or weighted (default)
+// QUEUE_BACKEND can be redis (default), streams or memory, streams consumer defaults to host name
+func NewQueueConfig() *QueueConfig {
+	consumer, err := os.Hostname()
+	if err != nil || consumer == "" {
+		consumer = "notifications"
+	}
+
+	return &QueueConfig{
+		Backend:         strings.ToLower(getEnv("QUEUE_BACKEND", "redis")),
+		StreamsGroup:    getEnv("QUEUE_STREAMS_GROUP", "dispatchers"),
+		StreamsConsumer: getEnv("QUEUE_STREAMS_CONSUMER", consumer),
+
+		Scheduling:   strings.ToLower(getEnv("QUEUE_SCHEDULING", "weighted")),
+		HighWeight:   getEnvInt("QUEUE_WEIGHT_HIGH", 6),
+		NormalWeight: getEnvInt("QUEUE_WEIGHT_NORMAL", 3),
+		BulkWeight:   getEnvInt("QUEUE_WEIGHT_BULK", 1),
+
+		PromoteInterval: getEnvDuration("QUEUE_PROMOTE_INTERVAL", time.Second),
+		LeaseTimeout:    getEnvDuration("QUEUE_LEASE_TIMEOUT", 2*time.Minute),
+		ReapInterval:    getEnvDuration("QUEUE_REAP_INTERVAL", 30*time.Second),
+		BlockTimeout:    getEnvDuration("QUEUE_BLOCK_TIMEOUT", 5*time.Second),
+
+		MaxScheduleAhead: getEnvDuration("QUEUE_MAX_SCHEDULE_AHEAD", 90*24*time.Hour),
+	}
+}
+
+// NewRetryConfig returns retry policy configurations instance
+func NewRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		MaxAttempts: getEnvInt("RETRY_MAX_ATTEMPTS", 5),
+		BaseDelay:   getEnvDuration("RETRY_BASE_DELAY", 30*time.Second),
+		MaxDelay:    getEnvDuration("RETRY_MAX_DELAY", time.Hour),
+	}
+}
+
+// NewStatusConfig returns delivery status configurations instance
+func NewStatusConfig() *StatusConfig {
+	return &StatusConfig{
+		TTL: getEnvDuration("STATUS_TTL", 7*24*time.Hour),
+	}
+}
+
+// NewTemplatesConfig returns templates configurations instance
+// TEMPLATES_SEED_DIR defaults to templates, set it to an empty value to not seed templates
+func NewTemplatesConfig() *TemplatesConfig {
+	return &TemplatesConfig{
+		SeedDir: getEnv("TEMPLATES_SEED_DIR", "templates"),
+	}
+}
+
+// NewIdempotencyConfig returns idempotency keys configurations instance
+func NewIdempotencyConfig() *IdempotencyConfig {
+	return &IdempotencyConfig{
+		TTL:        getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		PendingTTL: getEnvDuration("IDEMPOTENCY_PENDING_TTL", time.Minute),
 	}
 }
 
@@ -84,3 +452,46 @@ func getEnv(key string, defaultVal string) string {
 
 	return defaultVal
 }
+
+// getEnvInt reads an integer environment variable or returns default value
+func getEnvInt(key string, defaultVal int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil {
+		return defaultVal
+	}
+
+	return value
+}
+
+// getEnvFloat reads a float environment variable or returns default value
+func getEnvFloat(key string, defaultVal float64) float64 {
+	value, err := strconv.ParseFloat(getEnv(key, ""), 64)
+	if err != nil {
+		return defaultVal
+	}
+
+	return value
+}
+
+// getEnvDuration reads a duration (eg: 30s, 5m) environment variable or returns default value
+func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil {
+		return defaultVal
+	}
+
+	return value
+}
+
+// getEnvList reads a comma separated environment variable, values are lower cased
+func getEnvList(key string, defaultVal string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultVal), ",") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
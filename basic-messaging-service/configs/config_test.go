@@ -0,0 +1,44 @@
+package configs
+
+import "testing"
+
+func TestTLSConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config TLSConfig
+		valid  bool
+	}{
+		{"plain text", TLSConfig{}, true},
+		{"tls", TLSConfig{CertFile: "server.crt", KeyFile: "server.key"}, true},
+		{"mutual tls", TLSConfig{CertFile: "server.crt", KeyFile: "server.key", ClientCAFile: "ca.crt"}, true},
+		{"client ca without certificate", TLSConfig{ClientCAFile: "ca.crt"}, false},
+		{"key without certificate", TLSConfig{KeyFile: "server.key"}, false},
+		{"certificate without key", TLSConfig{CertFile: "server.crt"}, false},
+	}
+
+	for _, test := range tests {
+		if err := test.config.Validate(); (err == nil) != test.valid {
+			t.Errorf("%s: Validate() error = %v, want valid %v", test.name, err, test.valid)
+		}
+	}
+}
+
+func TestSMTPConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config SMTPConfig
+		valid  bool
+	}{
+		{"starttls and plain", SMTPConfig{Encryption: "starttls", Auth: "plain"}, true},
+		{"tls and login", SMTPConfig{Encryption: "tls", Auth: "login"}, true},
+		{"no encryption or auth", SMTPConfig{Encryption: "none", Auth: "none"}, true},
+		{"unknown auth", SMTPConfig{Encryption: "starttls", Auth: "plian", Username: "user"}, false},
+		{"unknown encryption", SMTPConfig{Encryption: "ssl", Auth: "plain"}, false},
+	}
+
+	for _, test := range tests {
+		if err := test.config.Validate(); (err == nil) != test.valid {
+			t.Errorf("%s: Validate() error = %v, want valid %v", test.name, err, test.valid)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package configs
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// defaultSenders => used when EMAIL_SENDERS is not set or cannot be parsed
+const defaultSenders = "Test User <test@test.com>"
+
+// Sender => verified sender identity, emails can only be sent from one of these
+type Sender struct {
+	Name  string
+	Email string
+}
+
+// EmailConfig => verified senders of email providers, Default is used when a message does not choose one
+type EmailConfig struct {
+	Senders []Sender
+	Default Sender
+}
+
+// AttachmentConfig => attachment limits, contents larger than InlineLimit are not kept in
+// queued messages but stored separately for TTL, longer when a message is scheduled so they outlive its retries
+type AttachmentConfig struct {
+	MaxSize      int
+	MaxTotalSize int
+	InlineLimit  int
+	TTL          time.Duration
+}
+
+// UnsubscribeConfig => signed one click unsubscribe links, added to emails which are not transactional
+// when URL (public url of the /unsubscribe endpoint) and Secret (signs the links) are set.
+// Messages without a category are transactional.
+type UnsubscribeConfig struct {
+	URL                     string
+	Secret                  string
+	TransactionalCategories []string
+}
+
+// NewEmailConfig returns email sender configurations instance
+// EMAIL_SENDERS is a comma separated address list (eg: Acme <noreply@acme.com>, support@acme.com),
+// EMAIL_DEFAULT_SENDER is email of the default sender, first sender when not set
+func NewEmailConfig() *EmailConfig {
+	addresses, err := mail.ParseAddressList(getEnv("EMAIL_SENDERS", defaultSenders))
+	if err != nil || len(addresses) == 0 {
+		addresses, _ = mail.ParseAddressList(defaultSenders)
+	}
+
+	config := &EmailConfig{}
+	for _, address := range addresses {
+		config.Senders = append(config.Senders, Sender{Name: address.Name, Email: address.Address})
+	}
+
+	config.Default = config.Senders[0]
+	if sender, ok := config.Sender(getEnv("EMAIL_DEFAULT_SENDER", "")); ok {
+		config.Default = sender
+	}
+
+	return config
+}
+
+// Sender => returns verified sender with given email (case insensitive), default sender for empty email
+func (c *EmailConfig) Sender(email string) (Sender, bool) {
+	if email == "" {
+		return c.Default, true
+	}
+
+	for _, sender := range c.Senders {
+		if strings.EqualFold(sender.Email, email) {
+			return sender, true
+		}
+	}
+	return Sender{}, false
+}
+
+// NewAttachmentConfig returns attachment limits instance, sizes are in bytes
+func NewAttachmentConfig() *AttachmentConfig {
+	return &AttachmentConfig{
+		MaxSize:      getEnvInt("ATTACHMENT_MAX_SIZE", 10<<20),
+		MaxTotalSize: getEnvInt("ATTACHMENT_MAX_TOTAL_SIZE", 20<<20),
+		InlineLimit:  getEnvInt("ATTACHMENT_INLINE_LIMIT", 64<<10),
+		TTL:          getEnvDuration("ATTACHMENT_TTL", 7*24*time.Hour),
+	}
+}
+
+// NewUnsubscribeConfig returns unsubscribe links configurations instance
+// TRANSACTIONAL_CATEGORIES is a comma separated list of categories which cannot be unsubscribed from
+func NewUnsubscribeConfig() *UnsubscribeConfig {
+	return &UnsubscribeConfig{
+		URL:                     getEnv("UNSUBSCRIBE_URL", ""),
+		Secret:                  getEnv("UNSUBSCRIBE_SECRET", ""),
+		TransactionalCategories: getEnvList("TRANSACTIONAL_CATEGORIES", "transactional"),
+	}
+}
+
+// IsTransactional => true when messages of category are transactional (always sent unless suppressed for all)
+func (c *UnsubscribeConfig) IsTransactional(category string) bool {
+	if category == "" {
+		return true
+	}
+
+	for _, transactional := range c.TransactionalCategories {
+		if strings.EqualFold(transactional, category) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package configs
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateRule => token bucket rule, Limit requests per Period with bursts up to Burst
+type RateRule struct {
+	Limit  int
+	Period time.Duration
+	Burst  int
+}
+
+// RateLimitConfig => rate limits per recipient, per provider and per calling service
+// Callers without a rule of their own use DefaultCaller. Nil rules are not limited.
+type RateLimitConfig struct {
+	Recipient     *RateRule
+	Providers     map[string]*RateRule
+	Callers       map[string]*RateRule
+	DefaultCaller *RateRule
+}
+
+// NewRateLimitConfig returns rate limit configurations instance
+// Rules are written as limit/period[:burst], eg: 10/1h or 100/1s:200
+// RATE_LIMIT_RECIPIENT => single rule applied to every recipient
+// RATE_LIMIT_PROVIDERS => provider=rule list, eg: sendgrid=100/1s,smtp=20/1s
+// RATE_LIMIT_CALLERS => caller=rule list, * is used for callers not in the list
+func NewRateLimitConfig() *RateLimitConfig {
+	callers := parseRateRules(getEnv("RATE_LIMIT_CALLERS", ""))
+	defaultCaller := callers["*"]
+	delete(callers, "*")
+
+	return &RateLimitConfig{
+		Recipient:     ParseRateRule(getEnv("RATE_LIMIT_RECIPIENT", "")),
+		Providers:     parseRateRules(getEnv("RATE_LIMIT_PROVIDERS", "")),
+		Callers:       callers,
+		DefaultCaller: defaultCaller,
+	}
+}
+
+// ParseRateRule => parses limit/period[:burst], returns nil for empty or invalid rule
+func ParseRateRule(value string) *RateRule {
+	value = strings.TrimSpace(value)
+	burst := ""
+	if i := strings.Index(value, ":"); i != -1 {
+		value, burst = value[:i], value[i+1:]
+	}
+
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+
+	limit, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || limit < 1 {
+		return nil
+	}
+	period, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || period <= 0 {
+		return nil
+	}
+
+	rule := &RateRule{Limit: limit, Period: period, Burst: limit}
+	if burst != "" {
+		if b, err := strconv.Atoi(strings.TrimSpace(burst)); err == nil && b > 0 {
+			rule.Burst = b
+		}
+	}
+
+	return rule
+}
+
+// parseRateRules => parses name=rule list, invalid entries are skipped
+func parseRateRules(value string) map[string]*RateRule {
+	rules := make(map[string]*RateRule)
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		if rule := ParseRateRule(parts[1]); name != "" && rule != nil {
+			rules[name] = rule
+		}
+	}
+
+	return rules
+}
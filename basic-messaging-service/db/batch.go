@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// BatchEntry => message of a batch with its queued status event
+// Message is held back in its scheduled set when SendAt is set, otherwise pushed to its queue.
+type BatchEntry struct {
+	Message *protos.MessageRequest
+	SendAt  time.Time
+	Event   *protos.StatusEvent
+}
+
+// EnqueueBatch => adds messages to their queues (or scheduled sets) and records their status in a single pipeline
+// Returns error of each entry, nil when it was queued. Status is recorded on a best effort basis.
+func (rc *Redis) EnqueueBatch(ctx context.Context, entries []*BatchEntry, statusTTL time.Duration) []error {
+	return rc.pipelineBatch(ctx, entries, statusTTL, func(pipe redis.Pipeliner, entry *BatchEntry, value string) redis.Cmder {
+		if entry.SendAt.IsZero() {
+			return pipe.LPush(ctx, QueueKey(entry.Message.GetPriority()), value)
+		}
+		return scheduleBatchEntry(ctx, pipe, entry, value)
+	})
+}
+
+// pipelineBatch => queues every entry with enqueue and records its status in a single pipeline
+// enqueue returns the command queueing the message.
+func (rc *Redis) pipelineBatch(ctx context.Context, entries []*BatchEntry, statusTTL time.Duration,
+	enqueue func(pipe redis.Pipeliner, entry *BatchEntry, value string) redis.Cmder) []error {
+	queued := make([]redis.Cmder, len(entries))
+	// failed commands keep their own error, so the pipeline error is not needed
+	_, _ = rc.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, entry := range entries {
+			queued[i] = enqueue(pipe, entry, proto.MarshalTextString(entry.Message))
+
+			if at, err := ptypes.Timestamp(entry.Event.GetAt()); err == nil {
+				recordStatus(ctx, pipe, entry.Message, entry.Event, at, "", statusTTL)
+			}
+		}
+		return nil
+	})
+
+	errs := make([]error, len(entries))
+	for i, cmd := range queued {
+		errs[i] = wrapError(cmd.Err())
+	}
+
+	return errs
+}
+
+// recordBatchStatus => records the status of every entry in a single pipeline, used by backends
+// which do not queue messages in redis. Status is recorded on a best effort basis.
+func (rc *Redis) recordBatchStatus(ctx context.Context, entries []*BatchEntry, statusTTL time.Duration) {
+	_, _ = rc.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, entry := range entries {
+			if at, err := ptypes.Timestamp(entry.Event.GetAt()); err == nil {
+				recordStatus(ctx, pipe, entry.Message, entry.Event, at, "", statusTTL)
+			}
+		}
+		return nil
+	})
+}
+
+// scheduleBatchEntry => adds message of entry to its scheduled set
+func scheduleBatchEntry(ctx context.Context, pipe redis.Pipeliner, entry *BatchEntry, value string) redis.Cmder {
+	return pipe.ZAdd(ctx, ScheduledKey(entry.Message.GetPriority()), &redis.Z{
+		Score:  float64(toMillis(entry.SendAt)),
+		Member: value,
+	})
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// BlobKey => key of content stored out of band (eg: attachments), content addressed by its sha256
+func BlobKey(reference string) string {
+	return "blob:" + reference
+}
+
+// storeBlobScript stores a blob for ttl, a blob already stored for longer keeps its ttl
+var storeBlobScript = redis.NewScript(`
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl == -1 or ttl >= tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`)
+
+// touchBlobScript extends ttl of a blob and returns its size, -1 when it does not exist
+// A blob already stored for longer keeps its ttl.
+var touchBlobScript = redis.NewScript(`
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl == -2 then
+	return -1
+end
+if ttl ~= -1 and ttl < tonumber(ARGV[1]) then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return redis.call('STRLEN', KEYS[1])
+`)
+
+// StoreBlob => stores content for ttl and returns its reference
+// Same content is stored once, storing it again only extends its ttl.
+func (rc *Redis) StoreBlob(ctx context.Context, content []byte, ttl time.Duration) (string, error) {
+	sum := sha256.Sum256(content)
+	reference := hex.EncodeToString(sum[:])
+
+	if err := storeBlobScript.Run(ctx, rc.client, []string{BlobKey(reference)}, content, ttl.Milliseconds()).Err(); err != nil {
+		return "", wrapError(err)
+	}
+	return reference, nil
+}
+
+// TouchBlob => extends ttl of stored content and returns its size, redis.Nil when it does not exist
+func (rc *Redis) TouchBlob(ctx context.Context, reference string, ttl time.Duration) (int64, error) {
+	size, err := touchBlobScript.Run(ctx, rc.client, []string{BlobKey(reference)}, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return 0, wrapError(err)
+	} else if size < 0 {
+		return 0, redis.Nil
+	}
+	return size, nil
+}
+
+// GetBlob => returns stored content, redis.Nil when it does not exist (or expired)
+func (rc *Redis) GetBlob(ctx context.Context, reference string) ([]byte, error) {
+	content, err := rc.client.Get(ctx, BlobKey(reference)).Bytes()
+	return content, wrapError(err)
+}
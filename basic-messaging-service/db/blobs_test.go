@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func TestBlobTTLIsOnlyExtended(t *testing.T) {
+	rc, server := newTestRedis(t)
+	ctx := context.Background()
+
+	reference, err := rc.StoreBlob(ctx, []byte("attachment"), 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("StoreBlob() error = %v", err)
+	}
+
+	// same content stored for a message sent now must not shorten the ttl of a scheduled one
+	if again, err := rc.StoreBlob(ctx, []byte("attachment"), time.Hour); err != nil || again != reference {
+		t.Fatalf("StoreBlob() = %s, %v, want %s", again, err, reference)
+	}
+	if size, err := rc.TouchBlob(ctx, reference, time.Hour); err != nil || size != int64(len("attachment")) {
+		t.Fatalf("TouchBlob() = %d, %v", size, err)
+	}
+	if ttl := server.TTL(BlobKey(reference)); ttl != 30*24*time.Hour {
+		t.Errorf("ttl = %v, want %v", ttl, 30*24*time.Hour)
+	}
+
+	if _, err := rc.TouchBlob(ctx, reference, 60*24*time.Hour); err != nil {
+		t.Fatalf("TouchBlob() error = %v", err)
+	}
+	if ttl := server.TTL(BlobKey(reference)); ttl != 60*24*time.Hour {
+		t.Errorf("ttl = %v, want %v", ttl, 60*24*time.Hour)
+	}
+
+	server.FastForward(61 * 24 * time.Hour)
+	if _, err := rc.TouchBlob(ctx, reference, time.Hour); err != redis.Nil {
+		t.Errorf("TouchBlob() of expired blob error = %v, want redis.Nil", err)
+	}
+}
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// Dead letter keys, hash of id => dead letter and sorted set of ids scored by failure time
+const (
+	DeadLettersKey      = "dead-letters"
+	DeadLettersIndexKey = "dead-letters:index"
+)
+
+// retryScript releases lease of a message and holds the updated message in scheduled set
+var retryScript = redis.NewScript(`
+redis.call('LREM', KEYS[1], -1, ARGV[1])
+redis.call('ZREM', KEYS[2], ARGV[1])
+redis.call('ZADD', KEYS[3], ARGV[3], ARGV[2])
+return 1
+`)
+
+// deadLetterScript releases lease of a message and stores it as a dead letter
+var deadLetterScript = redis.NewScript(`
+redis.call('LREM', KEYS[1], -1, ARGV[1])
+redis.call('ZREM', KEYS[2], ARGV[1])
+redis.call('HSET', KEYS[3], ARGV[2], ARGV[3])
+redis.call('ZADD', KEYS[4], ARGV[4], ARGV[2])
+return 1
+`)
+
+// replayScript removes a dead letter and pushes its message to queue.
+// Nothing is pushed when dead letter was already removed (eg: replayed concurrently).
+var replayScript = redis.NewScript(`
+if redis.call('HDEL', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[1])
+redis.call('LPUSH', KEYS[3], ARGV[2])
+return 1
+`)
+
+// Retry => releases lease and holds updated message in its scheduled set until retryAt
+func (rc *Redis) Retry(ctx context.Context, lease *Lease, message *protos.MessageRequest, retryAt time.Time) error {
+	keys := []string{ProcessingKey(lease.Queue), LeasesKey(lease.Queue), ScheduledKey(message.GetPriority())}
+	return retryScript.Run(ctx, rc.client, keys, lease.Payload, proto.MarshalTextString(message), toMillis(retryAt)).Err()
+}
+
+// DeadLetter => releases lease and stores the message as a dead letter
+func (rc *Redis) DeadLetter(ctx context.Context, lease *Lease, deadLetter *protos.DeadLetter) error {
+	failedAt, err := ptypes.Timestamp(deadLetter.GetFailedAt())
+	if err != nil {
+		return err
+	}
+
+	keys := []string{ProcessingKey(lease.Queue), LeasesKey(lease.Queue), DeadLettersKey, DeadLettersIndexKey}
+	return deadLetterScript.Run(ctx, rc.client, keys,
+		lease.Payload, deadLetter.GetId(), proto.MarshalTextString(deadLetter), toMillis(failedAt)).Err()
+}
+
+// GetDeadLetter => returns dead letter with given id, redis.Nil if it does not exist
+func (rc *Redis) GetDeadLetter(ctx context.Context, id string) (*protos.DeadLetter, error) {
+	value, err := rc.client.HGet(ctx, DeadLettersKey, id).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var deadLetter protos.DeadLetter
+	if err := proto.UnmarshalText(value, &deadLetter); err != nil {
+		return nil, &InvalidMessageError{Payload: value, Err: err}
+	}
+
+	return &deadLetter, nil
+}
+
+// ListDeadLetters => returns dead letters newest first and total number of dead letters
+func (rc *Redis) ListDeadLetters(ctx context.Context, offset, limit int64) ([]*protos.DeadLetter, int64, error) {
+	total, err := rc.client.ZCard(ctx, DeadLettersIndexKey).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids, err := rc.client.ZRevRange(ctx, DeadLettersIndexKey, offset, offset+limit-1).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, total, err
+	}
+
+	values, err := rc.client.HMGet(ctx, DeadLettersKey, ids...).Result()
+	if err != nil {
+		return nil, total, err
+	}
+
+	deadLetters := make([]*protos.DeadLetter, 0, len(values))
+	for _, value := range values {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var deadLetter protos.DeadLetter
+		if err := proto.UnmarshalText(text, &deadLetter); err != nil {
+			continue
+		}
+		deadLetters = append(deadLetters, &deadLetter)
+	}
+
+	return deadLetters, total, nil
+}
+
+// ReplayDeadLetter => removes dead letter and pushes message to given queue
+// Returns false when dead letter does not exist anymore
+func (rc *Redis) ReplayDeadLetter(ctx context.Context, id, queue string, message *protos.MessageRequest) (bool, error) {
+	keys := []string{DeadLettersKey, DeadLettersIndexKey, queue}
+	replayed, err := replayScript.Run(ctx, rc.client, keys, id, proto.MarshalTextString(message)).Int()
+
+	return replayed == 1, err
+}
+
+// PurgeDeadLetters => removes given dead letters, all of them when no ids are given
+// Returns number of purged dead letters
+func (rc *Redis) PurgeDeadLetters(ctx context.Context, ids ...string) (int64, error) {
+	if len(ids) == 0 {
+		total, err := rc.client.HLen(ctx, DeadLettersKey).Result()
+		if err != nil {
+			return 0, err
+		}
+		return total, rc.client.Del(ctx, DeadLettersKey, DeadLettersIndexKey).Err()
+	}
+
+	members := make([]interface{}, len(ids))
+	for i, id := range ids {
+		members[i] = id
+	}
+
+	purged, err := rc.client.HDel(ctx, DeadLettersKey, ids...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, rc.client.ZRem(ctx, DeadLettersIndexKey, members...).Err()
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func TestRetryReleasesLease(t *testing.T) {
+	rc, server := newTestRedis(t)
+	ctx := context.Background()
+
+	message := testMessage("m1", protos.Priority_NORMAL)
+	rc.Push(ctx, NormalPriorityQueue, message)
+	lease, _ := rc.Reserve(ctx, NormalPriorityQueue, time.Minute)
+
+	lease.Message.Attempts = 1
+	retryAt := time.Now().Add(time.Minute)
+	if err := rc.Retry(ctx, lease, lease.Message, retryAt); err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if server.Exists(ProcessingKey(NormalPriorityQueue)) || server.Exists(LeasesKey(NormalPriorityQueue)) {
+		t.Error("retried message is still in flight")
+	}
+
+	promoted, err := rc.PromoteDue(ctx, protos.Priority_NORMAL, retryAt, 10)
+	if err != nil || promoted != 1 {
+		t.Fatalf("PromoteDue() = %d, %v, want 1", promoted, err)
+	}
+	retried, err := rc.Pop(ctx, NormalPriorityQueue)
+	if err != nil || retried.GetAttempts() != 1 {
+		t.Errorf("retried message = %v, %v, want updated attempts", retried, err)
+	}
+}
+
+func TestDeadLetterAndReplay(t *testing.T) {
+	rc, server := newTestRedis(t)
+	ctx := context.Background()
+
+	rc.Push(ctx, NormalPriorityQueue, testMessage("m1", protos.Priority_NORMAL))
+	lease, _ := rc.Reserve(ctx, NormalPriorityQueue, time.Minute)
+
+	deadLetter := &protos.DeadLetter{Id: "m1", Message: lease.Message, Error: "invalid address", FailedAt: ptypes.TimestampNow()}
+	if err := rc.DeadLetter(ctx, lease, deadLetter); err != nil {
+		t.Fatalf("DeadLetter() error = %v", err)
+	}
+	if server.Exists(ProcessingKey(NormalPriorityQueue)) {
+		t.Error("dead lettered message is still in flight")
+	}
+
+	deadLetters, total, err := rc.ListDeadLetters(ctx, 0, 10)
+	if err != nil || total != 1 || len(deadLetters) != 1 || deadLetters[0].GetError() != "invalid address" {
+		t.Fatalf("ListDeadLetters() = %v, %d, %v", deadLetters, total, err)
+	}
+
+	replayed, err := rc.ReplayDeadLetter(ctx, "m1", NormalPriorityQueue, deadLetter.GetMessage())
+	if err != nil || !replayed {
+		t.Fatalf("ReplayDeadLetter() = %v, %v", replayed, err)
+	}
+	// a dead letter is replayed only once
+	if replayed, _ := rc.ReplayDeadLetter(ctx, "m1", NormalPriorityQueue, deadLetter.GetMessage()); replayed {
+		t.Error("dead letter was replayed twice")
+	}
+	if items, _ := server.List(NormalPriorityQueue); len(items) != 1 {
+		t.Errorf("queue has %d messages, want the replayed one", len(items))
+	}
+	if _, err := rc.GetDeadLetter(ctx, "m1"); err == nil {
+		t.Error("replayed dead letter still exists")
+	}
+}
+
+func TestPurgeDeadLetters(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"m1", "m2", "m3"} {
+		rc.Push(ctx, NormalPriorityQueue, testMessage(id, protos.Priority_NORMAL))
+		lease, _ := rc.Reserve(ctx, NormalPriorityQueue, time.Minute)
+		rc.DeadLetter(ctx, lease, &protos.DeadLetter{Id: id, Message: lease.Message, FailedAt: ptypes.TimestampNow()})
+	}
+
+	if purged, err := rc.PurgeDeadLetters(ctx, "m1", "unknown"); err != nil || purged != 1 {
+		t.Fatalf("PurgeDeadLetters(m1) = %d, %v, want 1", purged, err)
+	}
+	if purged, err := rc.PurgeDeadLetters(ctx); err != nil || purged != 2 {
+		t.Fatalf("PurgeDeadLetters() = %d, %v, want 2", purged, err)
+	}
+	if _, total, _ := rc.ListDeadLetters(ctx, 0, 10); total != 0 {
+		t.Errorf("%d dead letters left after purge", total)
+	}
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/proto"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// DeliveriesChannel => pub/sub channel of delivery events, every recorded status is published to it
+const DeliveriesChannel = "deliveries"
+
+// deliveryEventsBuffer => events buffered for a slow subscriber before redis client starts dropping them
+const deliveryEventsBuffer = 1000
+
+// publishDelivery => queues publish of a status event on pipe
+func publishDelivery(ctx context.Context, pipe redis.Pipeliner, message *protos.MessageRequest, event *protos.StatusEvent) {
+	pipe.Publish(ctx, DeliveriesChannel, deliveryPayload(message, event))
+}
+
+func deliveryPayload(message *protos.MessageRequest, event *protos.StatusEvent) string {
+	return proto.MarshalTextString(&protos.DeliveryEvent{
+		Id:       message.GetId(),
+		Type:     message.GetType(),
+		To:       message.GetTo(),
+		Attempts: message.GetAttempts(),
+		Event:    event,
+	})
+}
+
+// SubscribeDeliveries => subscribes to delivery events, events are sent to returned channel until ctx is done
+// Events published while there is no subscriber are not kept.
+func (rc *Redis) SubscribeDeliveries(ctx context.Context) (<-chan *protos.DeliveryEvent, error) {
+	pubsub := rc.client.Subscribe(ctx, DeliveriesChannel)
+	// wait for subscription, so no event is missed after returning
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, wrapError(err)
+	}
+
+	messages := pubsub.ChannelSize(deliveryEventsBuffer)
+	events := make(chan *protos.DeliveryEvent)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var event protos.DeliveryEvent
+				if err := proto.UnmarshalText(message.Payload, &event); err != nil {
+					continue
+				}
+
+				select {
+				case events <- &event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
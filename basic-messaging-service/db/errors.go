@@ -10,10 +10,19 @@ func (err *OperationError) Error() string {
 }
 
 // DownError when its not a redis.Nil response, in this case the database is down
-type DownError struct{}
+type DownError struct {
+	Err error
+}
 
 func (dbe *DownError) Error() string {
-	return "Database is down"
+	if dbe.Err == nil {
+		return "Database is down"
+	}
+	return "Database is down: " + dbe.Err.Error()
+}
+
+func (dbe *DownError) Unwrap() error {
+	return dbe.Err
 }
 
 // CreateDatabaseError when cannot perform set on database
@@ -31,3 +40,13 @@ type NotImplementedDatabaseError struct {
 func (err *NotImplementedDatabaseError) Error() string {
 	return err.database + " not implemented"
 }
+
+// InvalidMessageError when a queued payload cannot be decoded into a message
+type InvalidMessageError struct {
+	Payload string
+	Err     error
+}
+
+func (err *InvalidMessageError) Error() string {
+	return "Invalid message in queue: " + err.Err.Error()
+}
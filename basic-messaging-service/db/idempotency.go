@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/proto"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// Idempotency record states
+const (
+	idempotencyPending = "pending"
+	idempotencyDone    = "done"
+)
+
+// IdempotencyRecord => response remembered for an idempotency key
+// Pending records belong to a request which is still in progress.
+type IdempotencyRecord struct {
+	Pending  bool
+	Response *protos.MessageResponse
+}
+
+// IdempotencyKey => key remembering response of a request, scoped by the calling service and the operation
+// Callers can not see (or collide with) each other's requests. Caller is escaped, so it can not contain a separator.
+func IdempotencyKey(caller, scope, key string) string {
+	return "idempotency:" + url.QueryEscape(caller) + ":" + scope + ":" + key
+}
+
+// claimScript returns existing record of the key, or creates a pending one
+var claimScript = redis.NewScript(`
+local record = redis.call('HMGET', KEYS[1], 'state', 'response')
+if record[1] then
+	return record
+end
+redis.call('HSET', KEYS[1], 'state', 'pending', 'response', ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return false
+`)
+
+// ClaimIdempotencyKey => claims key for a new request, pending response is stored until it completes
+// Returns the existing record when key was already claimed, nil otherwise.
+func (rc *Redis) ClaimIdempotencyKey(
+	ctx context.Context, key string, pending *protos.MessageResponse, ttl time.Duration) (*IdempotencyRecord, error) {
+	result, err := claimScript.Run(ctx, rc.client, []string{key},
+		proto.MarshalTextString(pending), ttl.Milliseconds()).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	values, _ := result.([]interface{})
+	if len(values) != 2 {
+		return nil, &OperationError{operation: "claim idempotency key"}
+	}
+	state, _ := values[0].(string)
+	text, _ := values[1].(string)
+
+	var response protos.MessageResponse
+	if err := proto.UnmarshalText(text, &response); err != nil {
+		return nil, &InvalidMessageError{Payload: text, Err: err}
+	}
+
+	return &IdempotencyRecord{
+		Pending:  state == idempotencyPending,
+		Response: &response,
+	}, nil
+}
+
+// CompleteIdempotencyKey => stores final response of key, remembered for ttl
+func (rc *Redis) CompleteIdempotencyKey(
+	ctx context.Context, key string, response *protos.MessageResponse, ttl time.Duration) error {
+	_, err := rc.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "state", idempotencyDone, "response", proto.MarshalTextString(response))
+		pipe.PExpire(ctx, key, ttl)
+		return nil
+	})
+
+	return err
+}
+
+// ReleaseIdempotencyKey => forgets key, so the request can be retried
+func (rc *Redis) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	return rc.client.Del(ctx, key).Err()
+}
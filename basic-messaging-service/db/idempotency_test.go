@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func TestIdempotencyKeyPerCaller(t *testing.T) {
+	if IdempotencyKey("billing", "send", "k1") == IdempotencyKey("alerts", "send", "k1") {
+		t.Error("callers share idempotency keys")
+	}
+	if IdempotencyKey("a:send", "queue", "k") == IdempotencyKey("a", "send", "queue:k") {
+		t.Error("caller with a separator collides with another caller")
+	}
+}
+
+func TestClaimIdempotencyKeyPerCaller(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	ctx := context.Background()
+
+	first := IdempotencyKey("billing", "send", "k1")
+	record, err := rc.ClaimIdempotencyKey(ctx, first, &protos.MessageResponse{Id: "m1"}, time.Minute)
+	if err != nil || record != nil {
+		t.Fatalf("ClaimIdempotencyKey() = %v, %v, want a new claim", record, err)
+	}
+
+	record, err = rc.ClaimIdempotencyKey(ctx, first, &protos.MessageResponse{Id: "m2"}, time.Minute)
+	if err != nil || record == nil || !record.Pending || record.Response.GetId() != "m1" {
+		t.Fatalf("ClaimIdempotencyKey() again = %v, %v, want pending m1", record, err)
+	}
+
+	other := IdempotencyKey("alerts", "send", "k1")
+	record, err = rc.ClaimIdempotencyKey(ctx, other, &protos.MessageResponse{Id: "m3"}, time.Minute)
+	if err != nil || record != nil {
+		t.Errorf("ClaimIdempotencyKey() of another caller = %v, %v, want a new claim", record, err)
+	}
+}
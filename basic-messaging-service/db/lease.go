@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/proto"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// Lease => a message reserved by a worker from a queue
+// Message stays in the processing list of the queue until it is acked. If the
+// worker dies before that, the reaper puts it back to queue once deadline passes.
+// ID identifies the message within its queue for backends which need it (stream entry id).
+type Lease struct {
+	Queue    string
+	ID       string
+	Payload  string
+	Message  *protos.MessageRequest
+	Deadline time.Time
+}
+
+// ProcessingKey => list holding in-flight messages of a queue
+func ProcessingKey(queue string) string {
+	return queue + ":processing"
+}
+
+// LeasesKey => sorted set of in-flight messages of a queue, scored by lease deadline
+func LeasesKey(queue string) string {
+	return queue + ":leases"
+}
+
+// reserveScript moves next message to processing list and records its lease deadline atomically
+var reserveScript = redis.NewScript(`
+local payload = redis.call('RPOPLPUSH', KEYS[1], KEYS[2])
+if not payload then
+	return false
+end
+redis.call('ZADD', KEYS[3], ARGV[1], payload)
+return payload
+`)
+
+// releaseScript removes message from processing list and its lease.
+// When a destination queue is given, message is pushed back to it (next in line).
+var releaseScript = redis.NewScript(`
+local removed = redis.call('LREM', KEYS[1], -1, ARGV[1])
+redis.call('ZREM', KEYS[2], ARGV[1])
+if removed > 0 and KEYS[3] then
+	redis.call('RPUSH', KEYS[3], ARGV[1])
+end
+return removed
+`)
+
+// reapScript returns messages with expired leases back to their queue.
+// In-flight messages without a lease (worker died right after a blocking reserve)
+// are given one, so they are returned once that lease expires.
+var reapScript = redis.NewScript(`
+local expired = redis.call('ZRANGEBYSCORE', KEYS[3], '-inf', ARGV[1])
+local requeued = 0
+for _, payload in ipairs(expired) do
+	redis.call('ZREM', KEYS[3], payload)
+	if redis.call('LREM', KEYS[2], -1, payload) > 0 then
+		redis.call('RPUSH', KEYS[1], payload)
+		requeued = requeued + 1
+	end
+end
+for _, payload in ipairs(redis.call('LRANGE', KEYS[2], 0, -1)) do
+	if not redis.call('ZSCORE', KEYS[3], payload) then
+		redis.call('ZADD', KEYS[3], ARGV[2], payload)
+	end
+end
+return requeued
+`)
+
+// Reserve => takes next message of queue under a lease of given duration
+// Returns redis.Nil when queue is empty
+func (rc *Redis) Reserve(ctx context.Context, key string, leaseFor time.Duration) (*Lease, error) {
+	deadline := time.Now().Add(leaseFor)
+	keys := []string{key, ProcessingKey(key), LeasesKey(key)}
+
+	payload, err := reserveScript.Run(ctx, rc.client, keys, toMillis(deadline)).Text()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return newLease(ctx, rc, &Lease{Queue: key, Payload: payload, Deadline: deadline})
+}
+
+// ReserveBlocking => like Reserve, but waits up to timeout for a message when queue is empty
+// Returns redis.Nil when no message arrived in time
+func (rc *Redis) ReserveBlocking(ctx context.Context, key string, leaseFor, timeout time.Duration) (*Lease, error) {
+	payload, err := rc.client.BRPopLPush(ctx, key, ProcessingKey(key), timeout).Result()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	deadline := time.Now().Add(leaseFor)
+	if err := rc.client.ZAdd(ctx, LeasesKey(key), &redis.Z{Score: float64(toMillis(deadline)), Member: payload}).Err(); err != nil {
+		// Message is in processing list, reaper gives it a lease and returns it to queue
+		return nil, wrapError(err)
+	}
+
+	return newLease(ctx, rc, &Lease{Queue: key, Payload: payload, Deadline: deadline})
+}
+
+// newLease => parses message of a reserved payload, message which can not be parsed is acked and
+// returned as InvalidMessageError
+func newLease(ctx context.Context, queue Queue, lease *Lease) (*Lease, error) {
+	var message protos.MessageRequest
+	if err := proto.UnmarshalText(lease.Payload, &message); err != nil {
+		// Message can never be processed, drop it instead of requeueing forever
+		_ = queue.Ack(ctx, lease)
+		return nil, &InvalidMessageError{Payload: lease.Payload, Err: err}
+	}
+	lease.Message = &message
+
+	return lease, nil
+}
+
+// ReserveAny => reserves from the first non empty queue, keys are tried in the given order
+// Returns redis.Nil when all the queues are empty
+func (rc *Redis) ReserveAny(ctx context.Context, leaseFor time.Duration, keys ...string) (*Lease, error) {
+	for _, key := range keys {
+		lease, err := rc.Reserve(ctx, key, leaseFor)
+		if err == redis.Nil {
+			continue
+		}
+		return lease, err
+	}
+
+	return nil, redis.Nil
+}
+
+// Ack => message is processed, removes it from processing list
+func (rc *Redis) Ack(ctx context.Context, lease *Lease) error {
+	keys := []string{ProcessingKey(lease.Queue), LeasesKey(lease.Queue)}
+	return releaseScript.Run(ctx, rc.client, keys, lease.Payload).Err()
+}
+
+// Requeue => gives message back to its queue, it will be the next one reserved
+func (rc *Redis) Requeue(ctx context.Context, lease *Lease) error {
+	keys := []string{ProcessingKey(lease.Queue), LeasesKey(lease.Queue), lease.Queue}
+	return releaseScript.Run(ctx, rc.client, keys, lease.Payload).Err()
+}
+
+// ReapExpired => returns messages whose lease expired before now to their queue
+// In-flight messages without lease get one of leaseFor. Returns number of requeued messages.
+func (rc *Redis) ReapExpired(ctx context.Context, key string, now time.Time, leaseFor time.Duration) (int, error) {
+	keys := []string{key, ProcessingKey(key), LeasesKey(key)}
+	return reapScript.Run(ctx, rc.client, keys, toMillis(now), toMillis(now.Add(leaseFor))).Int()
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
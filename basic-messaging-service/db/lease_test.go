@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func TestReserveAndAck(t *testing.T) {
+	rc, server := newTestRedis(t)
+	ctx := context.Background()
+
+	if _, err := rc.Push(ctx, NormalPriorityQueue, testMessage("m1", protos.Priority_NORMAL)); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	lease, err := rc.Reserve(ctx, NormalPriorityQueue, time.Minute)
+	if err != nil || lease.Message.GetId() != "m1" {
+		t.Fatalf("Reserve() = %v, %v", lease, err)
+	}
+	if items, _ := server.List(ProcessingKey(NormalPriorityQueue)); len(items) != 1 {
+		t.Fatalf("processing list has %d messages, want 1", len(items))
+	}
+	if _, err := rc.Reserve(ctx, NormalPriorityQueue, time.Minute); err != redis.Nil {
+		t.Fatalf("Reserve() of empty queue error = %v, want redis.Nil", err)
+	}
+
+	if err := rc.Ack(ctx, lease); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	if server.Exists(ProcessingKey(NormalPriorityQueue)) || server.Exists(LeasesKey(NormalPriorityQueue)) {
+		t.Error("acked message is still in flight")
+	}
+}
+
+func TestRequeue(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"m1", "m2"} {
+		if _, err := rc.Push(ctx, NormalPriorityQueue, testMessage(id, protos.Priority_NORMAL)); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+	}
+
+	lease, _ := rc.Reserve(ctx, NormalPriorityQueue, time.Minute)
+	if err := rc.Requeue(ctx, lease); err != nil {
+		t.Fatalf("Requeue() error = %v", err)
+	}
+
+	// requeued message is next in line
+	again, err := rc.Reserve(ctx, NormalPriorityQueue, time.Minute)
+	if err != nil || again.Message.GetId() != "m1" {
+		t.Fatalf("Reserve() = %v, %v, want m1", again, err)
+	}
+}
+
+func TestReapExpired(t *testing.T) {
+	rc, server := newTestRedis(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"m1", "m2"} {
+		if _, err := rc.Push(ctx, NormalPriorityQueue, testMessage(id, protos.Priority_NORMAL)); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+	}
+	short, _ := rc.Reserve(ctx, NormalPriorityQueue, time.Second)
+	long, _ := rc.Reserve(ctx, NormalPriorityQueue, time.Hour)
+
+	now := time.Now()
+	if requeued, err := rc.ReapExpired(ctx, NormalPriorityQueue, now, time.Minute); err != nil || requeued != 0 {
+		t.Fatalf("ReapExpired() before deadline = %d, %v, want 0", requeued, err)
+	}
+
+	requeued, err := rc.ReapExpired(ctx, NormalPriorityQueue, now.Add(2*time.Second), time.Minute)
+	if err != nil || requeued != 1 {
+		t.Fatalf("ReapExpired() = %d, %v, want 1", requeued, err)
+	}
+	if items, _ := server.List(NormalPriorityQueue); len(items) != 1 || items[0] != short.Payload {
+		t.Fatalf("queue = %v, want expired message", items)
+	}
+	if items, _ := server.List(ProcessingKey(NormalPriorityQueue)); len(items) != 1 || items[0] != long.Payload {
+		t.Fatalf("processing list = %v, want message with valid lease", items)
+	}
+
+	// ack of a worker which lost its lease does not remove the requeued message
+	if err := rc.Ack(ctx, short); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	if items, _ := server.List(NormalPriorityQueue); len(items) != 1 {
+		t.Error("late ack removed the requeued message")
+	}
+}
+
+func TestReapGivesLeaseToMessagesWithout(t *testing.T) {
+	rc, server := newTestRedis(t)
+	ctx := context.Background()
+
+	// worker died right after a blocking reserve, before recording the lease
+	payload := "id: \"m1\""
+	server.Lpush(ProcessingKey(NormalPriorityQueue), payload)
+
+	now := time.Now()
+	if requeued, err := rc.ReapExpired(ctx, NormalPriorityQueue, now, time.Minute); err != nil || requeued != 0 {
+		t.Fatalf("ReapExpired() = %d, %v, want 0", requeued, err)
+	}
+	if score, err := server.ZScore(LeasesKey(NormalPriorityQueue), payload); err != nil ||
+		int64(score) != toMillis(now.Add(time.Minute)) {
+		t.Fatalf("lease score = %v, %v, want deadline now + lease", score, err)
+	}
+
+	requeued, err := rc.ReapExpired(ctx, NormalPriorityQueue, now.Add(2*time.Minute), time.Minute)
+	if err != nil || requeued != 1 {
+		t.Fatalf("ReapExpired() after lease = %d, %v, want 1", requeued, err)
+	}
+}
+
+func TestReserveDropsInvalidMessage(t *testing.T) {
+	rc, server := newTestRedis(t)
+	ctx := context.Background()
+
+	server.Lpush(NormalPriorityQueue, "not a message {")
+
+	_, err := rc.Reserve(ctx, NormalPriorityQueue, time.Minute)
+	if _, ok := err.(*InvalidMessageError); !ok {
+		t.Fatalf("Reserve() error = %v, want InvalidMessageError", err)
+	}
+	if server.Exists(ProcessingKey(NormalPriorityQueue)) {
+		t.Error("invalid message is still in flight")
+	}
+}
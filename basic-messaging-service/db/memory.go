@@ -0,0 +1,349 @@
+package db
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// memoryQueue => queues messages in process memory, meant for local development
+// Messages and dead letters are lost on restart and are not shared between instances.
+// Delivery status is still kept in redis.
+type memoryQueue struct {
+	rc *Redis
+
+	mu sync.Mutex
+	// queues => payloads of every queue, next message first
+	queues map[string][]string
+	// leases => in-flight messages by lease id
+	leases map[string]*Lease
+	// scheduled => messages of every scheduled set, ordered by send time
+	scheduled map[string][]scheduledMessage
+	// arrived => closed (and removed) once a message is added to the queue, wakes blocked workers
+	arrived map[string]chan struct{}
+	// deadLetters => dead lettered messages by id
+	deadLetters map[string]*protos.DeadLetter
+	lastID      int64
+}
+
+type scheduledMessage struct {
+	payload string
+	at      time.Time
+}
+
+func newMemoryQueue(rc *Redis) *memoryQueue {
+	return &memoryQueue{
+		rc:        rc,
+		queues:    make(map[string][]string),
+		leases:    make(map[string]*Lease),
+		scheduled: make(map[string][]scheduledMessage),
+		arrived:   make(map[string]chan struct{}),
+
+		deadLetters: make(map[string]*protos.DeadLetter),
+	}
+}
+
+// push => adds payload to queue, first in line when next is set, must be called with mu held
+func (q *memoryQueue) push(key, payload string, next bool) {
+	if next {
+		q.queues[key] = append([]string{payload}, q.queues[key]...)
+	} else {
+		q.queues[key] = append(q.queues[key], payload)
+	}
+
+	if arrived, ok := q.arrived[key]; ok {
+		close(arrived)
+		delete(q.arrived, key)
+	}
+}
+
+// schedule => adds payload to scheduled set keeping it ordered by send time, must be called with mu held
+func (q *memoryQueue) schedule(key, payload string, at time.Time) {
+	scheduled := q.scheduled[key]
+	i := sort.Search(len(scheduled), func(i int) bool { return scheduled[i].at.After(at) })
+	scheduled = append(scheduled, scheduledMessage{})
+	copy(scheduled[i+1:], scheduled[i:])
+	scheduled[i] = scheduledMessage{payload: payload, at: at}
+	q.scheduled[key] = scheduled
+}
+
+// reserve => takes next message of queue under a lease, nil when queue is empty, must be called with mu held
+func (q *memoryQueue) reserve(key string, leaseFor time.Duration) *Lease {
+	payloads := q.queues[key]
+	if len(payloads) == 0 {
+		return nil
+	}
+	q.queues[key] = payloads[1:]
+
+	q.lastID++
+	lease := &Lease{
+		Queue:    key,
+		ID:       strconv.FormatInt(q.lastID, 10),
+		Payload:  payloads[0],
+		Deadline: time.Now().Add(leaseFor),
+	}
+	q.leases[lease.ID] = lease
+
+	reserved := *lease
+	return &reserved
+}
+
+func (q *memoryQueue) Push(_ context.Context, key string, message *protos.MessageRequest) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.push(key, proto.MarshalTextString(message), false)
+	return true, nil
+}
+
+// EnqueueBatch => messages are queued in memory first, then their status is recorded in redis
+func (q *memoryQueue) EnqueueBatch(ctx context.Context, entries []*BatchEntry, statusTTL time.Duration) []error {
+	q.mu.Lock()
+	for _, entry := range entries {
+		value := proto.MarshalTextString(entry.Message)
+		if entry.SendAt.IsZero() {
+			q.push(QueueKey(entry.Message.GetPriority()), value, false)
+		} else {
+			q.schedule(ScheduledKey(entry.Message.GetPriority()), value, entry.SendAt)
+		}
+	}
+	q.mu.Unlock()
+
+	q.rc.recordBatchStatus(ctx, entries, statusTTL)
+	return make([]error, len(entries))
+}
+
+func (q *memoryQueue) Schedule(_ context.Context, message *protos.MessageRequest, sendAt time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.schedule(ScheduledKey(message.GetPriority()), proto.MarshalTextString(message), sendAt)
+	return nil
+}
+
+func (q *memoryQueue) PromoteDue(_ context.Context, priority protos.Priority, now time.Time, limit int) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	key := ScheduledKey(priority)
+	scheduled := q.scheduled[key]
+	due := 0
+	for due < len(scheduled) && due < limit && !scheduled[due].at.After(now) {
+		q.push(QueueKey(priority), scheduled[due].payload, false)
+		due++
+	}
+	q.scheduled[key] = scheduled[due:]
+
+	return due, nil
+}
+
+func (q *memoryQueue) PopAny(ctx context.Context, keys ...string) (*protos.MessageRequest, error) {
+	lease, err := q.ReserveAny(ctx, 0, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	return lease.Message, q.Ack(ctx, lease)
+}
+
+func (q *memoryQueue) ReserveAny(ctx context.Context, leaseFor time.Duration, keys ...string) (*Lease, error) {
+	q.mu.Lock()
+	var lease *Lease
+	for _, key := range keys {
+		if lease = q.reserve(key, leaseFor); lease != nil {
+			break
+		}
+	}
+	q.mu.Unlock()
+
+	if lease == nil {
+		return nil, redis.Nil
+	}
+	return newLease(ctx, q, lease)
+}
+
+// ReserveBlocking => returns redis.Nil when no message arrived in time or ctx is done
+func (q *memoryQueue) ReserveBlocking(ctx context.Context, key string, leaseFor, timeout time.Duration) (*Lease, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		q.mu.Lock()
+		lease := q.reserve(key, leaseFor)
+		arrived, ok := q.arrived[key]
+		if lease == nil && !ok {
+			arrived = make(chan struct{})
+			q.arrived[key] = arrived
+		}
+		q.mu.Unlock()
+
+		if lease != nil {
+			return newLease(ctx, q, lease)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, redis.Nil
+		case <-timer.C:
+			return nil, redis.Nil
+		case <-arrived:
+		}
+	}
+}
+
+func (q *memoryQueue) Ack(_ context.Context, lease *Lease) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.leases, lease.ID)
+	return nil
+}
+
+func (q *memoryQueue) Retry(_ context.Context, lease *Lease, message *protos.MessageRequest, retryAt time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.leases, lease.ID)
+	q.schedule(ScheduledKey(message.GetPriority()), proto.MarshalTextString(message), retryAt)
+	return nil
+}
+
+func (q *memoryQueue) DeadLetter(_ context.Context, lease *Lease, deadLetter *protos.DeadLetter) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.leases, lease.ID)
+	q.deadLetters[deadLetter.GetId()] = proto.Clone(deadLetter).(*protos.DeadLetter)
+	return nil
+}
+
+// ReapExpired => every in-flight message has a lease, so leaseFor is not needed
+func (q *memoryQueue) ReapExpired(_ context.Context, key string, now time.Time, _ time.Duration) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var expired []*Lease
+	for _, lease := range q.leases {
+		if lease.Queue == key && !lease.Deadline.After(now) {
+			expired = append(expired, lease)
+		}
+	}
+	// latest deadline is pushed first, so the longest waiting message ends up next in line
+	sort.Slice(expired, func(i, j int) bool { return expired[i].Deadline.After(expired[j].Deadline) })
+
+	for _, lease := range expired {
+		delete(q.leases, lease.ID)
+		q.push(key, lease.Payload, true)
+	}
+
+	return len(expired), nil
+}
+
+func (q *memoryQueue) GetDeadLetter(_ context.Context, id string) (*protos.DeadLetter, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	deadLetter, ok := q.deadLetters[id]
+	if !ok {
+		return nil, redis.Nil
+	}
+	return proto.Clone(deadLetter).(*protos.DeadLetter), nil
+}
+
+func (q *memoryQueue) ListDeadLetters(_ context.Context, offset, limit int64) ([]*protos.DeadLetter, int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	deadLetters := make([]*protos.DeadLetter, 0, len(q.deadLetters))
+	for _, deadLetter := range q.deadLetters {
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	// newest first, like the redis index which orders ties by id
+	sort.Slice(deadLetters, func(i, j int) bool {
+		a, _ := ptypes.Timestamp(deadLetters[i].GetFailedAt())
+		b, _ := ptypes.Timestamp(deadLetters[j].GetFailedAt())
+		if a.Equal(b) {
+			return deadLetters[i].GetId() > deadLetters[j].GetId()
+		}
+		return a.After(b)
+	})
+
+	total := int64(len(deadLetters))
+	if offset >= total || limit <= 0 {
+		return nil, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	page := make([]*protos.DeadLetter, 0, end-offset)
+	for _, deadLetter := range deadLetters[offset:end] {
+		page = append(page, proto.Clone(deadLetter).(*protos.DeadLetter))
+	}
+	return page, total, nil
+}
+
+// ReplayDeadLetter => message is pushed to the queue with the same lock the dead letter is removed with,
+// so it is replayed once
+func (q *memoryQueue) ReplayDeadLetter(_ context.Context, id, queue string, message *protos.MessageRequest) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.deadLetters[id]; !ok {
+		return false, nil
+	}
+	delete(q.deadLetters, id)
+	q.push(queue, proto.MarshalTextString(message), false)
+	return true, nil
+}
+
+func (q *memoryQueue) PurgeDeadLetters(_ context.Context, ids ...string) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(ids) == 0 {
+		purged := int64(len(q.deadLetters))
+		q.deadLetters = make(map[string]*protos.DeadLetter)
+		return purged, nil
+	}
+
+	var purged int64
+	for _, id := range ids {
+		if _, ok := q.deadLetters[id]; ok {
+			delete(q.deadLetters, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (q *memoryQueue) QueueStats(_ context.Context, priorities ...protos.Priority) ([]QueueStats, int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := make([]QueueStats, len(priorities))
+	for i, priority := range priorities {
+		key := QueueKey(priority)
+		stats[i] = QueueStats{
+			Queue:     key,
+			Ready:     int64(len(q.queues[key])),
+			Scheduled: int64(len(q.scheduled[ScheduledKey(priority)])),
+		}
+		for _, lease := range q.leases {
+			if lease.Queue == key {
+				stats[i].Processing++
+			}
+		}
+	}
+
+	return stats, int64(len(q.deadLetters)), nil
+}
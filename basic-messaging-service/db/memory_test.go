@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/ptypes"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func TestMemoryDeadLettersStayInMemory(t *testing.T) {
+	rc, server := newTestRedis(t)
+	q := newMemoryQueue(rc)
+	ctx := context.Background()
+	// dead letters of the memory backend do not need redis
+	server.Close()
+
+	failedAt := time.Now()
+	for i, id := range []string{"m1", "m2", "m3"} {
+		q.Push(ctx, NormalPriorityQueue, testMessage(id, protos.Priority_NORMAL))
+		lease, err := q.ReserveAny(ctx, time.Minute, NormalPriorityQueue)
+		if err != nil {
+			t.Fatalf("ReserveAny() error = %v", err)
+		}
+
+		at, _ := ptypes.TimestampProto(failedAt.Add(time.Duration(i) * time.Second))
+		deadLetter := &protos.DeadLetter{Id: id, Message: lease.Message, Error: "invalid address", FailedAt: at}
+		if err := q.DeadLetter(ctx, lease, deadLetter); err != nil {
+			t.Fatalf("DeadLetter() error = %v", err)
+		}
+	}
+
+	deadLetters, total, err := q.ListDeadLetters(ctx, 1, 10)
+	if err != nil || total != 3 || len(deadLetters) != 2 || deadLetters[0].GetId() != "m2" || deadLetters[1].GetId() != "m1" {
+		t.Fatalf("ListDeadLetters() = %v, %d, %v, want m2 and m1 of 3", deadLetters, total, err)
+	}
+	if _, err := q.GetDeadLetter(ctx, "m4"); err != redis.Nil {
+		t.Errorf("GetDeadLetter() of unknown id error = %v, want redis.Nil", err)
+	}
+
+	deadLetter, err := q.GetDeadLetter(ctx, "m3")
+	if err != nil {
+		t.Fatalf("GetDeadLetter() error = %v", err)
+	}
+	if replayed, err := q.ReplayDeadLetter(ctx, "m3", NormalPriorityQueue, deadLetter.GetMessage()); err != nil || !replayed {
+		t.Fatalf("ReplayDeadLetter() = %v, %v", replayed, err)
+	}
+	if replayed, _ := q.ReplayDeadLetter(ctx, "m3", NormalPriorityQueue, deadLetter.GetMessage()); replayed {
+		t.Error("dead letter was replayed twice")
+	}
+
+	stats, deadLetterCount, err := q.QueueStats(ctx, protos.Priority_NORMAL)
+	if err != nil || deadLetterCount != 2 || stats[0].Ready != 1 || stats[0].Processing != 0 {
+		t.Fatalf("QueueStats() = %+v, %d, %v, want 1 ready and 2 dead letters", stats, deadLetterCount, err)
+	}
+
+	if purged, err := q.PurgeDeadLetters(ctx, "m1", "m4"); err != nil || purged != 1 {
+		t.Errorf("PurgeDeadLetters() = %d, %v, want 1", purged, err)
+	}
+	if purged, err := q.PurgeDeadLetters(ctx); err != nil || purged != 1 {
+		t.Errorf("PurgeDeadLetters() of all = %d, %v, want 1", purged, err)
+	}
+}
+
+func TestMemoryEnqueueBatch(t *testing.T) {
+	rc, server := newTestRedis(t)
+	q := newMemoryQueue(rc)
+	ctx := context.Background()
+
+	entries := []*BatchEntry{
+		{Message: testMessage("now", protos.Priority_HIGH), Event: &protos.StatusEvent{At: ptypes.TimestampNow()}},
+		{Message: testMessage("later", protos.Priority_HIGH), SendAt: time.Now().Add(time.Hour),
+			Event: &protos.StatusEvent{At: ptypes.TimestampNow()}},
+	}
+	for i, err := range q.EnqueueBatch(ctx, entries, time.Hour) {
+		if err != nil {
+			t.Errorf("entry %d error = %v", i, err)
+		}
+	}
+
+	stats, _, _ := q.QueueStats(ctx, protos.Priority_HIGH)
+	if stats[0].Ready != 1 || stats[0].Scheduled != 1 {
+		t.Errorf("QueueStats() = %+v, want 1 ready and 1 scheduled", stats[0])
+	}
+	// messages stay in memory, only their status is in redis
+	if server.Exists(QueueKey(protos.Priority_HIGH)) || !server.Exists(StatusKey("now")) || !server.Exists(StatusKey("later")) {
+		t.Errorf("redis keys = %v, want only status keys", server.Keys())
+	}
+
+	// queueing does not depend on redis
+	server.Close()
+	if errs := q.EnqueueBatch(ctx, entries[:1], time.Hour); errs[0] != nil {
+		t.Errorf("EnqueueBatch() without redis error = %v", errs[0])
+	}
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/proto"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// PreferencesKey => hash of lower cased recipient => preferences
+const PreferencesKey = "preferences"
+
+// SetPreferences => stores preferences of recipient, replacing existing ones
+func (rc *Redis) SetPreferences(ctx context.Context, preferences *protos.Preferences) error {
+	return rc.client.HSet(ctx, PreferencesKey,
+		strings.ToLower(preferences.GetRecipient()), proto.MarshalTextString(preferences)).Err()
+}
+
+// GetPreferences => returns preferences of recipient, empty preferences when none are stored
+func (rc *Redis) GetPreferences(ctx context.Context, recipient string) (*protos.Preferences, error) {
+	text, err := rc.client.HGet(ctx, PreferencesKey, strings.ToLower(recipient)).Result()
+	if err == redis.Nil {
+		return &protos.Preferences{Recipient: recipient}, nil
+	} else if err != nil {
+		return nil, wrapError(err)
+	}
+
+	var preferences protos.Preferences
+	if err := proto.UnmarshalText(text, &preferences); err != nil {
+		return nil, &InvalidMessageError{Payload: text, Err: err}
+	}
+	return &preferences, nil
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// Queue backends
+const (
+	ListsBackend   = "redis"
+	StreamsBackend = "streams"
+	MemoryBackend  = "memory"
+)
+
+// Queue => priority queues, scheduled messages and leases of messages being dispatched
+// Queues are named by QueueKey, redis.Nil is returned when there is no message.
+// Dead letters are kept by the backend with the leases they are released from.
+type Queue interface {
+	// Push => adds message to the queue with given key
+	Push(ctx context.Context, key string, message *protos.MessageRequest) (bool, error)
+	// EnqueueBatch => adds messages to their queues (or scheduled sets) and records their status,
+	// returns error of each entry
+	EnqueueBatch(ctx context.Context, entries []*BatchEntry, statusTTL time.Duration) []error
+	// Schedule => holds message until sendAt, it is moved to its queue by PromoteDue
+	Schedule(ctx context.Context, message *protos.MessageRequest, sendAt time.Time) error
+	// PromoteDue => moves at most limit messages due at now to queue of the priority
+	PromoteDue(ctx context.Context, priority protos.Priority, now time.Time, limit int) (int, error)
+	// PopAny => removes next message from the first non empty queue, without a lease
+	PopAny(ctx context.Context, keys ...string) (*protos.MessageRequest, error)
+
+	// ReserveAny => takes next message of the first non empty queue under a lease
+	ReserveAny(ctx context.Context, leaseFor time.Duration, keys ...string) (*Lease, error)
+	// ReserveBlocking => like ReserveAny for one queue, waits up to timeout when it is empty
+	ReserveBlocking(ctx context.Context, key string, leaseFor, timeout time.Duration) (*Lease, error)
+	// Ack => message of lease is processed
+	Ack(ctx context.Context, lease *Lease) error
+	// Retry => releases lease and holds updated message until retryAt
+	Retry(ctx context.Context, lease *Lease, message *protos.MessageRequest, retryAt time.Time) error
+	// DeadLetter => releases lease and stores message as a dead letter
+	DeadLetter(ctx context.Context, lease *Lease, deadLetter *protos.DeadLetter) error
+	// ReapExpired => makes messages of queue with leases expired before now available again
+	ReapExpired(ctx context.Context, key string, now time.Time, leaseFor time.Duration) (int, error)
+
+	// GetDeadLetter => returns dead letter with given id, redis.Nil if it does not exist
+	GetDeadLetter(ctx context.Context, id string) (*protos.DeadLetter, error)
+	// ListDeadLetters => returns dead letters newest first and total number of dead letters
+	ListDeadLetters(ctx context.Context, offset, limit int64) ([]*protos.DeadLetter, int64, error)
+	// ReplayDeadLetter => removes dead letter and pushes message to queue, false when it does not exist
+	ReplayDeadLetter(ctx context.Context, id, queue string, message *protos.MessageRequest) (bool, error)
+	// PurgeDeadLetters => removes given dead letters, all of them when no ids are given, returns number purged
+	PurgeDeadLetters(ctx context.Context, ids ...string) (int64, error)
+	// QueueStats => message counts of queues of the given priorities and number of dead letters
+	QueueStats(ctx context.Context, priorities ...protos.Priority) ([]QueueStats, int64, error)
+}
+
+// NewQueue => returns queue of configured backend, messages of redis lists backend are kept in rc
+func NewQueue(config *configs.QueueConfig, rc *Redis) (Queue, error) {
+	switch config.Backend {
+	case ListsBackend, "":
+		return rc, nil
+	case StreamsBackend:
+		return newStreamsQueue(rc, config.StreamsGroup, config.StreamsConsumer), nil
+	case MemoryBackend:
+		return newMemoryQueue(rc), nil
+	default:
+		return nil, &NotImplementedDatabaseError{database: config.Backend + " queue"}
+	}
+}
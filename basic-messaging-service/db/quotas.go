@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// QuotaKey => messages sent by a client on a day (UTC)
+func QuotaKey(client string, day time.Time) string {
+	return "quota:" + client + ":" + day.UTC().Format("2006-01-02")
+}
+
+// takeQuotaScript counts ARGV[1] messages unless count would go over ARGV[2],
+// counter expires ARGV[3] ms after it is first set so days do not pile up.
+// Returns 1 and the new count when counted, 0 and the current count otherwise.
+var takeQuotaScript = redis.NewScript(`
+local count = tonumber(redis.call('GET', KEYS[1]) or '0')
+local n = tonumber(ARGV[1])
+if count + n > tonumber(ARGV[2]) then
+	return {0, count}
+end
+count = redis.call('INCRBY', KEYS[1], n)
+if count == n then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+return {1, count}
+`)
+
+// TakeQuota => counts n messages of client for today, nothing is counted when count would go over limit
+// Returns false when client is over its quota, and messages counted today.
+func (rc *Redis) TakeQuota(ctx context.Context, client string, n, limit int64) (bool, int64, error) {
+	now := time.Now()
+	// kept for a day after the day ends, so count can still be looked at
+	ttl := 48 * time.Hour
+	result, err := takeQuotaScript.Run(ctx, rc.client, []string{QuotaKey(client, now)}, n, limit, ttl.Milliseconds()).Result()
+	if err != nil {
+		return false, 0, wrapError(err)
+	}
+
+	values, _ := result.([]interface{})
+	if len(values) != 2 {
+		return false, 0, &OperationError{operation: "take quota"}
+	}
+	allowed, _ := values[0].(int64)
+	count, _ := values[1].(int64)
+
+	return allowed == 1, count, nil
+}
+
+// refundQuotaScript uncounts ARGV[1] messages, count does not go below zero
+var refundQuotaScript = redis.NewScript(`
+local count = tonumber(redis.call('GET', KEYS[1]) or '0')
+local n = math.min(tonumber(ARGV[1]), count)
+if n <= 0 then
+	return count
+end
+return redis.call('DECRBY', KEYS[1], n)
+`)
+
+// RefundQuota => uncounts n messages of client for today (eg: messages which were not sent after all)
+func (rc *Redis) RefundQuota(ctx context.Context, client string, n int64) error {
+	return wrapError(refundQuotaScript.Run(ctx, rc.client, []string{QuotaKey(client, time.Now())}, n).Err())
+}
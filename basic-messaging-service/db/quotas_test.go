@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTakeAndRefundQuota(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	ctx := context.Background()
+
+	for i := int64(1); i <= 2; i++ {
+		if allowed, count, err := rc.TakeQuota(ctx, "rest-api", 1, 2); err != nil || !allowed || count != i {
+			t.Fatalf("TakeQuota() = %v, %d, %v, want allowed with count %d", allowed, count, err, i)
+		}
+	}
+	if allowed, count, err := rc.TakeQuota(ctx, "rest-api", 1, 2); err != nil || allowed || count != 2 {
+		t.Fatalf("TakeQuota() over limit = %v, %d, %v, want rejected with count 2", allowed, count, err)
+	}
+	// quotas are per client
+	if allowed, _, err := rc.TakeQuota(ctx, "ops", 1, 2); err != nil || !allowed {
+		t.Fatalf("TakeQuota() of other client = %v, %v, want allowed", allowed, err)
+	}
+
+	if err := rc.RefundQuota(ctx, "rest-api", 1); err != nil {
+		t.Fatalf("RefundQuota() error = %v", err)
+	}
+	if allowed, count, err := rc.TakeQuota(ctx, "rest-api", 1, 2); err != nil || !allowed || count != 2 {
+		t.Fatalf("TakeQuota() after refund = %v, %d, %v, want allowed with count 2", allowed, count, err)
+	}
+
+	// refunds never make count negative
+	if err := rc.RefundQuota(ctx, "rest-api", 5); err != nil {
+		t.Fatalf("RefundQuota() error = %v", err)
+	}
+	if allowed, count, err := rc.TakeQuota(ctx, "rest-api", 2, 2); err != nil || !allowed || count != 2 {
+		t.Fatalf("TakeQuota() = %v, %d, %v, want allowed with count 2", allowed, count, err)
+	}
+}
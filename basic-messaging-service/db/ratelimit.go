@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+)
+
+// RecipientBucketKey => rate limit bucket of a recipient (email address, phone number)
+func RecipientBucketKey(recipient string) string {
+	return "ratelimit:recipient:" + strings.ToLower(recipient)
+}
+
+// ProviderBucketKey => rate limit bucket of a provider (sendgrid, smtp, twilio, etc)
+func ProviderBucketKey(provider string) string {
+	return "ratelimit:provider:" + provider
+}
+
+// CallerBucketKey => rate limit bucket of a calling service
+func CallerBucketKey(caller string) string {
+	return "ratelimit:caller:" + caller
+}
+
+// Bucket => a token bucket key and the rule it is limited by
+type Bucket struct {
+	Key  string
+	Rule *configs.RateRule
+}
+
+// takeTokensScript takes one token from every bucket, or none of them when any bucket is empty.
+// Buckets refill continuously, tokens and last refill time are kept in a hash per bucket.
+// ARGV => now (ms), then capacity and refill rate (tokens per ms) of every bucket.
+// Returns 1 and 0 when allowed, 0 and ms to wait for a token otherwise.
+var takeTokensScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local tokens = {}
+local wait = 0
+for i, key in ipairs(KEYS) do
+	local capacity = tonumber(ARGV[i * 2])
+	local rate = tonumber(ARGV[i * 2 + 1])
+	local bucket = redis.call('HMGET', key, 'tokens', 'ts')
+	local available = tonumber(bucket[1])
+	local ts = tonumber(bucket[2])
+	if available == nil or ts == nil then
+		available = capacity
+		ts = now
+	end
+	available = math.min(capacity, available + math.max(0, now - ts) * rate)
+	if available < 1 then
+		-- rounding of the division must not add a millisecond to a whole wait
+		wait = math.max(wait, math.ceil((1 - available) / rate - 1e-6))
+	end
+	tokens[i] = available
+end
+if wait > 0 then
+	return {0, wait}
+end
+for i, key in ipairs(KEYS) do
+	local capacity = tonumber(ARGV[i * 2])
+	local rate = tonumber(ARGV[i * 2 + 1])
+	redis.call('HSET', key, 'tokens', tokens[i] - 1, 'ts', now)
+	redis.call('PEXPIRE', key, math.ceil(capacity / rate) + 1000)
+end
+return {1, 0}
+`)
+
+// TakeTokens => takes a token from every bucket, all or nothing
+// Returns false and how long to wait when any of the buckets is empty.
+func (rc *Redis) TakeTokens(ctx context.Context, buckets ...Bucket) (bool, time.Duration, error) {
+	if len(buckets) == 0 {
+		return true, 0, nil
+	}
+
+	keys := make([]string, len(buckets))
+	args := []interface{}{toMillis(time.Now())}
+	for i, bucket := range buckets {
+		keys[i] = bucket.Key
+		perMillis := float64(bucket.Rule.Limit) / float64(bucket.Rule.Period.Milliseconds())
+		args = append(args, bucket.Rule.Burst, perMillis)
+	}
+
+	result, err := takeTokensScript.Run(ctx, rc.client, keys, args...).Result()
+	if err != nil {
+		return false, 0, err
+	}
+
+	values, _ := result.([]interface{})
+	if len(values) != 2 {
+		return false, 0, &OperationError{operation: "take rate limit tokens"}
+	}
+	allowed, _ := values[0].(int64)
+	wait, _ := values[1].(int64)
+
+	return allowed == 1, time.Duration(wait) * time.Millisecond, nil
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+)
+
+func TestTakeTokensBurst(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	ctx := context.Background()
+	bucket := Bucket{Key: RecipientBucketKey("user@example.com"), Rule: &configs.RateRule{Limit: 1, Period: time.Hour, Burst: 3}}
+
+	for i := 0; i < 3; i++ {
+		if allowed, _, err := rc.TakeTokens(ctx, bucket); err != nil || !allowed {
+			t.Fatalf("TakeTokens() %d = %v, %v, want allowed within burst", i, allowed, err)
+		}
+	}
+
+	allowed, wait, err := rc.TakeTokens(ctx, bucket)
+	if err != nil || allowed {
+		t.Fatalf("TakeTokens() = %v, %v, want rejected once burst is used", allowed, err)
+	}
+	if wait <= 59*time.Minute || wait > time.Hour {
+		t.Errorf("wait = %v, want about an hour for the next token", wait)
+	}
+}
+
+func TestTakeTokensRefill(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	ctx := context.Background()
+	bucket := Bucket{Key: ProviderBucketKey("sendgrid"), Rule: &configs.RateRule{Limit: 20, Period: time.Second, Burst: 1}}
+
+	if allowed, _, _ := rc.TakeTokens(ctx, bucket); !allowed {
+		t.Fatal("first token rejected")
+	}
+	if allowed, wait, _ := rc.TakeTokens(ctx, bucket); allowed || wait <= 0 || wait > 50*time.Millisecond {
+		t.Fatalf("TakeTokens() = %v, wait %v, want rejected for up to 50ms", allowed, wait)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if allowed, _, _ := rc.TakeTokens(ctx, bucket); !allowed {
+		t.Error("token was not refilled")
+	}
+}
+
+func TestTakeTokensAllOrNothing(t *testing.T) {
+	rc, server := newTestRedis(t)
+	ctx := context.Background()
+	open := Bucket{Key: CallerBucketKey("rest-api"), Rule: &configs.RateRule{Limit: 100, Period: time.Second, Burst: 100}}
+	empty := Bucket{Key: RecipientBucketKey("user@example.com"), Rule: &configs.RateRule{Limit: 1, Period: time.Hour, Burst: 1}}
+
+	if allowed, _, _ := rc.TakeTokens(ctx, empty); !allowed {
+		t.Fatal("first token rejected")
+	}
+	if allowed, _, _ := rc.TakeTokens(ctx, open, empty); allowed {
+		t.Fatal("allowed although one bucket is empty")
+	}
+
+	// no token was taken from the open bucket
+	if server.Exists(open.Key) {
+		tokens := server.HGet(open.Key, "tokens")
+		t.Errorf("open bucket has %s tokens, want untouched", tokens)
+	}
+}
+
+func TestTakeTokensWithoutBuckets(t *testing.T) {
+	rc, _ := newTestRedis(t)
+
+	if allowed, _, err := rc.TakeTokens(context.Background()); !allowed || err != nil {
+		t.Errorf("TakeTokens() = %v, %v, want allowed", allowed, err)
+	}
+}
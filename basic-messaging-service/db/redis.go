@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/go-redis/redis/v8"
+	"github.com/go-redis/redis/v8/redisext"
 
 	"github.com/golang/protobuf/proto"
 
@@ -11,6 +12,26 @@ import (
 	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
 )
 
+// Queue keys for each message priority
+// Normal priority keeps using "default" key, so already queued messages are not lost
+const (
+	HighPriorityQueue   = "high"
+	NormalPriorityQueue = "default"
+	BulkPriorityQueue   = "bulk"
+)
+
+// QueueKey => returns queue key for given priority
+func QueueKey(priority protos.Priority) string {
+	switch priority {
+	case protos.Priority_HIGH:
+		return HighPriorityQueue
+	case protos.Priority_BULK:
+		return BulkPriorityQueue
+	default:
+		return NormalPriorityQueue
+	}
+}
+
 type Redis struct {
 	client *redis.Client
 }
@@ -23,10 +44,17 @@ func NewRedisClient(serverConfig *configs.ServerConfig) *Redis {
 	}
 
 	client := redis.NewClient(opts)
+	// commands get spans when they are run within a traced request
+	client.AddHook(redisext.OpenTelemetryHook{})
 
 	return &Redis{client}
 }
 
+// Ping => checks that redis is reachable
+func (rc *Redis) Ping(ctx context.Context) error {
+	return wrapError(rc.client.Ping(ctx).Err())
+}
+
 func (rc *Redis) Push(ctx context.Context, key string, message *protos.MessageRequest) (bool, error) {
 	value := proto.MarshalTextString(message)
 	result := rc.client.LPush(ctx, key, value)
@@ -57,3 +85,29 @@ func (rc *Redis) Pop(ctx context.Context, key string) (*protos.MessageRequest, e
 
 	return &message, nil
 }
+
+// PopAny => pops from the first non empty queue, keys are tried in the given order
+// Returns redis.Nil when all the queues are empty
+func (rc *Redis) PopAny(ctx context.Context, keys ...string) (*protos.MessageRequest, error) {
+	for _, key := range keys {
+		message, err := rc.Pop(ctx, key)
+		if err == redis.Nil {
+			continue
+		}
+		return message, wrapError(err)
+	}
+
+	return nil, redis.Nil
+}
+
+// wrapError => redis.Nil is returned as it is (no data), any other error means redis is unreachable
+func wrapError(err error) error {
+	if err == nil || err == redis.Nil {
+		return err
+	}
+	if _, ok := err.(*InvalidMessageError); ok {
+		return err
+	}
+
+	return &DownError{Err: err}
+}
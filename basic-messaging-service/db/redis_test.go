@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// newTestRedis => client of an in-process redis server which is closed when test ends
+func newTestRedis(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("unable to start redis: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	return &Redis{client: redis.NewClient(&redis.Options{Addr: server.Addr()})}, server
+}
+
+func testMessage(id string, priority protos.Priority) *protos.MessageRequest {
+	return &protos.MessageRequest{Id: id, To: "user@example.com", Msg: "hello", Priority: priority}
+}
+
+func TestPopAnyFollowsKeyOrder(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	ctx := context.Background()
+
+	for _, message := range []*protos.MessageRequest{
+		testMessage("bulk", protos.Priority_BULK),
+		testMessage("normal-1", protos.Priority_NORMAL),
+		testMessage("normal-2", protos.Priority_NORMAL),
+	} {
+		if _, err := rc.Push(ctx, QueueKey(message.GetPriority()), message); err != nil {
+			t.Fatalf("Push() error = %v", err)
+		}
+	}
+
+	keys := []string{HighPriorityQueue, NormalPriorityQueue, BulkPriorityQueue}
+	for _, want := range []string{"normal-1", "normal-2", "bulk"} {
+		message, err := rc.PopAny(ctx, keys...)
+		if err != nil || message.GetId() != want {
+			t.Fatalf("PopAny() = %v, %v, want %s", message.GetId(), err, want)
+		}
+	}
+	if _, err := rc.PopAny(ctx, keys...); err != redis.Nil {
+		t.Errorf("PopAny() of empty queues error = %v, want redis.Nil", err)
+	}
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/proto"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// ScheduledKey => sorted set holding future messages of given priority, scored by send time
+func ScheduledKey(priority protos.Priority) string {
+	return "scheduled:" + QueueKey(priority)
+}
+
+// promoteScript moves due messages from a scheduled set to its queue.
+// Runs atomically inside redis, so a message is promoted exactly once even
+// with multiple service instances or a restart in between.
+var promoteScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, member in ipairs(due) do
+	redis.call('ZREM', KEYS[1], member)
+	redis.call('LPUSH', KEYS[2], member)
+end
+return #due
+`)
+
+// Schedule => holds message in scheduled set of its priority until sendAt
+func (rc *Redis) Schedule(ctx context.Context, message *protos.MessageRequest, sendAt time.Time) error {
+	value := proto.MarshalTextString(message)
+	return rc.client.ZAdd(ctx, ScheduledKey(message.GetPriority()), &redis.Z{
+		Score:  float64(toMillis(sendAt)),
+		Member: value,
+	}).Err()
+}
+
+// PromoteDue => moves at most limit messages due at now from scheduled set to queue
+// Returns number of promoted messages
+func (rc *Redis) PromoteDue(ctx context.Context, priority protos.Priority, now time.Time, limit int) (int, error) {
+	keys := []string{ScheduledKey(priority), QueueKey(priority)}
+	return promoteScript.Run(ctx, rc.client, keys, toMillis(now), limit).Int()
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func TestPromoteDue(t *testing.T) {
+	rc, server := newTestRedis(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	schedule := map[string]time.Duration{"past": -time.Minute, "now": 0, "later": time.Hour}
+	for id, in := range schedule {
+		if err := rc.Schedule(ctx, testMessage(id, protos.Priority_NORMAL), now.Add(in)); err != nil {
+			t.Fatalf("Schedule() error = %v", err)
+		}
+	}
+	if err := rc.Schedule(ctx, testMessage("high", protos.Priority_HIGH), now); err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+
+	promoted, err := rc.PromoteDue(ctx, protos.Priority_NORMAL, now, 10)
+	if err != nil || promoted != 2 {
+		t.Fatalf("PromoteDue() = %d, %v, want 2", promoted, err)
+	}
+
+	// oldest due message is promoted first, so it is dispatched first
+	for _, want := range []string{"past", "now"} {
+		message, err := rc.Pop(ctx, NormalPriorityQueue)
+		if err != nil || message.GetId() != want {
+			t.Fatalf("Pop() = %v, %v, want %s", message.GetId(), err, want)
+		}
+	}
+
+	// promoted messages are removed, so they are never promoted twice
+	if promoted, _ := rc.PromoteDue(ctx, protos.Priority_NORMAL, now, 10); promoted != 0 {
+		t.Errorf("PromoteDue() promoted %d messages again", promoted)
+	}
+	if members, _ := server.ZMembers(ScheduledKey(protos.Priority_NORMAL)); len(members) != 1 {
+		t.Errorf("scheduled set has %d messages, want the later one", len(members))
+	}
+	if members, _ := server.ZMembers(ScheduledKey(protos.Priority_HIGH)); len(members) != 1 {
+		t.Errorf("high priority message was promoted with normal ones")
+	}
+}
+
+func TestPromoteDueLimit(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, id := range []string{"a", "b", "c"} {
+		if err := rc.Schedule(ctx, testMessage(id, protos.Priority_BULK), now.Add(-time.Second)); err != nil {
+			t.Fatalf("Schedule() error = %v", err)
+		}
+	}
+
+	for _, want := range []int{2, 1, 0} {
+		promoted, err := rc.PromoteDue(ctx, protos.Priority_BULK, now, 2)
+		if err != nil || promoted != want {
+			t.Fatalf("PromoteDue() = %d, %v, want %d", promoted, err, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// QueueStats => number of messages of a priority queue in each stage
+type QueueStats struct {
+	Queue      string
+	Ready      int64
+	Processing int64
+	Scheduled  int64
+}
+
+// QueueStats => returns message counts of queues of the given priorities, in one round trip
+// Dead letters are counted too, they are shared by all the queues.
+func (rc *Redis) QueueStats(ctx context.Context, priorities ...protos.Priority) ([]QueueStats, int64, error) {
+	type counts struct {
+		ready, processing, scheduled *redis.IntCmd
+	}
+	cmds := make([]counts, len(priorities))
+	var deadLetters *redis.IntCmd
+	_, err := rc.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, priority := range priorities {
+			cmds[i].ready = pipe.LLen(ctx, QueueKey(priority))
+			cmds[i].processing = pipe.LLen(ctx, ProcessingKey(QueueKey(priority)))
+			cmds[i].scheduled = pipe.ZCard(ctx, ScheduledKey(priority))
+		}
+		deadLetters = pipe.ZCard(ctx, DeadLettersIndexKey)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	stats := make([]QueueStats, len(priorities))
+	for i, priority := range priorities {
+		stats[i] = QueueStats{
+			Queue:      QueueKey(priority),
+			Ready:      cmds[i].ready.Val(),
+			Processing: cmds[i].processing.Val(),
+			Scheduled:  cmds[i].scheduled.Val(),
+		}
+	}
+
+	return stats, deadLetters.Val(), nil
+}
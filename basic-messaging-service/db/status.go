@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// StatusKey => hash holding current delivery status of a message
+func StatusKey(id string) string {
+	return "status:" + id
+}
+
+// StatusHistoryKey => list of status events of a message, oldest first
+func StatusHistoryKey(id string) string {
+	return "status:" + id + ":history"
+}
+
+// Status hash fields
+const (
+	statusState            = "state"
+	statusType             = "type"
+	statusTo               = "to"
+	statusCaller           = "caller"
+	statusProvider         = "provider"
+	statusProviderResponse = "provider_response"
+	statusAttempts         = "attempts"
+	statusCreatedAt        = "created_at"
+	statusUpdatedAt        = "updated_at"
+)
+
+// RecordStatus => records a state change of message, both status and history expire after ttl
+// Change is published to DeliveriesChannel as well.
+// Provider fields are only overwritten when event has a provider.
+func (rc *Redis) RecordStatus(
+	ctx context.Context, message *protos.MessageRequest, event *protos.StatusEvent, response string, ttl time.Duration) error {
+	at, err := ptypes.Timestamp(event.GetAt())
+	if err != nil {
+		return err
+	}
+
+	_, err = rc.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		recordStatus(ctx, pipe, message, event, at, response, ttl)
+		return nil
+	})
+
+	return err
+}
+
+// recordStatus => queues status commands of an event on pipe, event is also published to delivery feed
+func recordStatus(ctx context.Context, pipe redis.Pipeliner,
+	message *protos.MessageRequest, event *protos.StatusEvent, at time.Time, response string, ttl time.Duration) {
+	id := message.GetId()
+	fields := map[string]interface{}{
+		statusState:     event.GetState().String(),
+		statusType:      message.GetType().String(),
+		statusTo:        message.GetTo(),
+		statusCaller:    message.GetCaller(),
+		statusAttempts:  message.GetAttempts(),
+		statusUpdatedAt: toMillis(at),
+	}
+	if event.GetProvider() != "" {
+		fields[statusProvider] = event.GetProvider()
+		fields[statusProviderResponse] = response
+	}
+
+	pipe.HSetNX(ctx, StatusKey(id), statusCreatedAt, toMillis(at))
+	pipe.HSet(ctx, StatusKey(id), fields)
+	pipe.RPush(ctx, StatusHistoryKey(id), proto.MarshalTextString(event))
+	pipe.Expire(ctx, StatusKey(id), ttl)
+	pipe.Expire(ctx, StatusHistoryKey(id), ttl)
+	publishDelivery(ctx, pipe, message, event)
+}
+
+// updateStatusScript records an event of an existing status and returns its message fields,
+// nothing is recorded when status does not exist
+// State only advances when event is not older than the current state (compared in seconds, providers
+// report seconds) and current state is not terminal (ARGV[5..]), otherwise event is only added to history.
+var updateStatusScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return false
+end
+local current = redis.call('HMGET', KEYS[1], 'state', 'updated_at')
+local advance = math.floor(tonumber(ARGV[2]) / 1000) >= math.floor((tonumber(current[2]) or 0) / 1000)
+for i = 5, #ARGV do
+	if current[1] == ARGV[i] then
+		advance = false
+	end
+end
+if advance then
+	redis.call('HSET', KEYS[1], 'state', ARGV[1], 'updated_at', ARGV[2])
+end
+redis.call('RPUSH', KEYS[2], ARGV[3])
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+redis.call('PEXPIRE', KEYS[2], ARGV[4])
+return redis.call('HMGET', KEYS[1], 'type', 'to', 'attempts')
+`)
+
+// TerminalStates => states a message does not leave, later provider events are only added to its history
+var TerminalStates = []protos.DeliveryState{
+	protos.DeliveryState_DEAD_LETTERED,
+	protos.DeliveryState_BOUNCED,
+	protos.DeliveryState_DROPPED,
+	protos.DeliveryState_SPAM_REPORTED,
+	protos.DeliveryState_UNSUBSCRIBED,
+}
+
+// UpdateStatus => records an event reported after message was sent (eg: by a provider webhook)
+// Events arriving late (older than current state, or after a terminal state) do not change the state.
+// Returns redis.Nil when status of message does not exist (unknown message or expired).
+func (rc *Redis) UpdateStatus(ctx context.Context, id string, event *protos.StatusEvent, ttl time.Duration) error {
+	at, err := ptypes.Timestamp(event.GetAt())
+	if err != nil {
+		return err
+	}
+
+	keys := []string{StatusKey(id), StatusHistoryKey(id)}
+	args := []interface{}{event.GetState().String(), toMillis(at), proto.MarshalTextString(event), ttl.Milliseconds()}
+	for _, state := range TerminalStates {
+		args = append(args, state.String())
+	}
+	result, err := updateStatusScript.Run(ctx, rc.client, keys, args...).Result()
+	if err != nil {
+		return err
+	}
+
+	values, _ := result.([]interface{})
+	if len(values) != 3 {
+		return &OperationError{operation: "update status"}
+	}
+	notificationType, _ := values[0].(string)
+	to, _ := values[1].(string)
+	attempts, _ := values[2].(string)
+	count, _ := strconv.Atoi(attempts)
+
+	message := &protos.MessageRequest{
+		Id:       id,
+		Type:     protos.NotificationType(protos.NotificationType_value[notificationType]),
+		To:       to,
+		Attempts: int32(count),
+	}
+	return rc.client.Publish(ctx, DeliveriesChannel, deliveryPayload(message, event)).Err()
+}
+
+// GetStatus => returns delivery status of message sent by caller with its history,
+// redis.Nil if not found or message was sent by another caller
+func (rc *Redis) GetStatus(ctx context.Context, id, caller string) (*protos.MessageStatus, error) {
+	fields, err := rc.client.HGetAll(ctx, StatusKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 || fields[statusCaller] != caller {
+		return nil, redis.Nil
+	}
+
+	attempts, _ := strconv.ParseInt(fields[statusAttempts], 10, 32)
+	status := &protos.MessageStatus{
+		Id:               id,
+		State:            protos.DeliveryState(protos.DeliveryState_value[fields[statusState]]),
+		Type:             protos.NotificationType(protos.NotificationType_value[fields[statusType]]),
+		To:               fields[statusTo],
+		Provider:         fields[statusProvider],
+		ProviderResponse: fields[statusProviderResponse],
+		Attempts:         int32(attempts),
+		CreatedAt:        fromMillis(fields[statusCreatedAt]),
+		UpdatedAt:        fromMillis(fields[statusUpdatedAt]),
+	}
+
+	events, err := rc.client.LRange(ctx, StatusHistoryKey(id), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range events {
+		var event protos.StatusEvent
+		if err := proto.UnmarshalText(value, &event); err == nil {
+			status.History = append(status.History, &event)
+		}
+	}
+
+	return status, nil
+}
+
+func fromMillis(value string) *timestamp.Timestamp {
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	ts, _ := ptypes.TimestampProto(time.Unix(0, millis*int64(time.Millisecond)))
+	return ts
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/ptypes"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func statusEvent(state protos.DeliveryState, at time.Time) *protos.StatusEvent {
+	timestamp, _ := ptypes.TimestampProto(at)
+	return &protos.StatusEvent{State: state, At: timestamp, Provider: "sendgrid"}
+}
+
+func TestUpdateStatusIgnoresLateEvents(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	ctx := context.Background()
+	sentAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	message := testMessage("1", protos.Priority_NORMAL)
+	if err := rc.RecordStatus(ctx, message, statusEvent(protos.DeliveryState_SENT, sentAt), "", time.Hour); err != nil {
+		t.Fatalf("RecordStatus() error = %v", err)
+	}
+
+	updates := []struct {
+		name  string
+		event *protos.StatusEvent
+		state protos.DeliveryState
+	}{
+		{"newer event", statusEvent(protos.DeliveryState_OPENED, sentAt.Add(20*time.Second)), protos.DeliveryState_OPENED},
+		{"late event", statusEvent(protos.DeliveryState_DELIVERED, sentAt.Add(10*time.Second)), protos.DeliveryState_OPENED},
+		{"terminal event", statusEvent(protos.DeliveryState_SPAM_REPORTED, sentAt.Add(30*time.Second)), protos.DeliveryState_SPAM_REPORTED},
+		{"after terminal", statusEvent(protos.DeliveryState_CLICKED, sentAt.Add(40*time.Second)), protos.DeliveryState_SPAM_REPORTED},
+	}
+
+	for i, update := range updates {
+		if err := rc.UpdateStatus(ctx, "1", update.event, time.Hour); err != nil {
+			t.Fatalf("%s: UpdateStatus() error = %v", update.name, err)
+		}
+
+		status, err := rc.GetStatus(ctx, "1", "")
+		if err != nil {
+			t.Fatalf("GetStatus() error = %v", err)
+		}
+		if status.GetState() != update.state {
+			t.Errorf("%s: state = %v, want %v", update.name, status.GetState(), update.state)
+		}
+		// every event is kept in history, even when it did not change the state
+		if len(status.GetHistory()) != i+2 {
+			t.Errorf("%s: history has %d events, want %d", update.name, len(status.GetHistory()), i+2)
+		}
+	}
+}
+
+func TestUpdateStatusSameSecondAdvances(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	ctx := context.Background()
+	sentAt := time.Now().Truncate(time.Second).Add(700 * time.Millisecond)
+
+	message := testMessage("1", protos.Priority_NORMAL)
+	if err := rc.RecordStatus(ctx, message, statusEvent(protos.DeliveryState_SENT, sentAt), "", time.Hour); err != nil {
+		t.Fatalf("RecordStatus() error = %v", err)
+	}
+
+	// provider timestamps have seconds only, so delivery reported within the same second is not late
+	delivered := statusEvent(protos.DeliveryState_DELIVERED, sentAt.Truncate(time.Second))
+	if err := rc.UpdateStatus(ctx, "1", delivered, time.Hour); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+	if status, err := rc.GetStatus(ctx, "1", ""); err != nil || status.GetState() != protos.DeliveryState_DELIVERED {
+		t.Errorf("GetStatus() = %v, %v, want delivered", status.GetState(), err)
+	}
+}
+
+func TestGetStatusPerCaller(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	ctx := context.Background()
+
+	message := testMessage("1", protos.Priority_NORMAL)
+	message.Caller = "billing"
+	if err := rc.RecordStatus(ctx, message, statusEvent(protos.DeliveryState_SENT, time.Now()), "", time.Hour); err != nil {
+		t.Fatalf("RecordStatus() error = %v", err)
+	}
+
+	if status, err := rc.GetStatus(ctx, "1", "billing"); err != nil || status.GetState() != protos.DeliveryState_SENT {
+		t.Errorf("GetStatus() of billing = %v, %v, want sent", status.GetState(), err)
+	}
+	for _, caller := range []string{"marketing", ""} {
+		if _, err := rc.GetStatus(ctx, "1", caller); err != redis.Nil {
+			t.Errorf("GetStatus() of %q error = %v, want redis.Nil", caller, err)
+		}
+	}
+}
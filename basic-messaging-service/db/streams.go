@@ -0,0 +1,324 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// StreamKey => stream holding messages of a queue, when queues are redis streams
+func StreamKey(queue string) string {
+	return "stream:" + queue
+}
+
+// streamField => field of a stream entry holding the message
+const streamField = "message"
+
+// reapBatchSize => max pending messages of a stream checked for expired leases in one redis call
+const reapBatchSize = 100
+
+// streamAckScript acks an entry and deletes it, so stream length stays the number of queued messages
+var streamAckScript = redis.NewScript(`
+redis.call('XACK', KEYS[1], ARGV[1], ARGV[2])
+return redis.call('XDEL', KEYS[1], ARGV[2])
+`)
+
+// streamRetryScript acks an entry and holds the updated message in scheduled set
+var streamRetryScript = redis.NewScript(`
+redis.call('XACK', KEYS[1], ARGV[1], ARGV[2])
+redis.call('XDEL', KEYS[1], ARGV[2])
+redis.call('ZADD', KEYS[2], ARGV[4], ARGV[3])
+return 1
+`)
+
+// streamDeadLetterScript acks an entry and stores its message as a dead letter
+var streamDeadLetterScript = redis.NewScript(`
+redis.call('XACK', KEYS[1], ARGV[1], ARGV[2])
+redis.call('XDEL', KEYS[1], ARGV[2])
+redis.call('HSET', KEYS[2], ARGV[3], ARGV[4])
+redis.call('ZADD', KEYS[3], ARGV[5], ARGV[3])
+return 1
+`)
+
+// streamReplayScript removes a dead letter and adds its message to stream.
+// Nothing is added when dead letter was already removed (eg: replayed concurrently).
+var streamReplayScript = redis.NewScript(`
+if redis.call('HDEL', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[1])
+redis.call('XADD', KEYS[3], '*', ARGV[2], ARGV[3])
+return 1
+`)
+
+// streamPromoteScript moves due messages from a scheduled set to stream of its queue
+var streamPromoteScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, member in ipairs(due) do
+	redis.call('ZREM', KEYS[1], member)
+	redis.call('XADD', KEYS[2], '*', ARGV[3], member)
+end
+return #due
+`)
+
+// streamReapScript adds entries idle for at least the lease duration to the stream again and acks them.
+// Claiming resets idle time, so an entry is requeued only once even with several reapers running.
+// Entries deleted meanwhile (acked by a slow worker) are only removed from pending list.
+var streamReapScript = redis.NewScript(`
+local requeued = 0
+for i = 4, #ARGV do
+	local entry = redis.call('XCLAIM', KEYS[1], ARGV[1], ARGV[2], ARGV[3], ARGV[i])[1]
+	if entry then
+		if entry[2] then
+			redis.call('XADD', KEYS[1], '*', unpack(entry[2]))
+			requeued = requeued + 1
+		end
+		redis.call('XACK', KEYS[1], ARGV[1], ARGV[i])
+		redis.call('XDEL', KEYS[1], ARGV[i])
+	end
+end
+return requeued
+`)
+
+// streamsQueue => queues messages in redis streams read by a consumer group
+// Stream keeps a message until it is acked, consumer group tracks messages being dispatched
+// (pending entries) and how long ago they were delivered, so no separate lease is stored.
+// Scheduled messages and dead letters are kept the same way as redis lists backend does.
+type streamsQueue struct {
+	rc       *Redis
+	group    string
+	consumer string
+	// groups => streams whose consumer group is known to exist
+	groups sync.Map
+}
+
+func newStreamsQueue(rc *Redis, group, consumer string) *streamsQueue {
+	return &streamsQueue{rc: rc, group: group, consumer: consumer}
+}
+
+// ensureGroup => creates consumer group (and stream) unless it exists
+// Group reads stream from its start, so messages added before the group was created are delivered too.
+func (q *streamsQueue) ensureGroup(ctx context.Context, stream string) error {
+	err := q.rc.client.XGroupCreateMkStream(ctx, stream, q.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	q.groups.Store(stream, true)
+	return nil
+}
+
+// isNoGroup => reports whether err is returned because stream or its consumer group does not exist
+func isNoGroup(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "NOGROUP")
+}
+
+// read => reads next new message of queue under a lease, waits up to block for it (not at all when negative)
+func (q *streamsQueue) read(ctx context.Context, queue string, leaseFor, block time.Duration) (*Lease, error) {
+	stream := StreamKey(queue)
+	if _, ok := q.groups.Load(stream); !ok {
+		if err := q.ensureGroup(ctx, stream); err != nil {
+			return nil, wrapError(err)
+		}
+	}
+
+	args := &redis.XReadGroupArgs{
+		Group:    q.group,
+		Consumer: q.consumer,
+		Streams:  []string{stream, ">"},
+		Count:    1,
+		Block:    block,
+	}
+	streams, err := q.rc.client.XReadGroup(ctx, args).Result()
+	if isNoGroup(err) {
+		// stream was deleted since the group was created (eg: redis was flushed)
+		if err := q.ensureGroup(ctx, stream); err != nil {
+			return nil, wrapError(err)
+		}
+		streams, err = q.rc.client.XReadGroup(ctx, args).Result()
+	}
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if len(streams) == 0 || len(streams[0].Messages) == 0 {
+		return nil, redis.Nil
+	}
+
+	entry := streams[0].Messages[0]
+	payload, _ := entry.Values[streamField].(string)
+	return newLease(ctx, q, &Lease{Queue: queue, ID: entry.ID, Payload: payload, Deadline: time.Now().Add(leaseFor)})
+}
+
+func (q *streamsQueue) Push(ctx context.Context, key string, message *protos.MessageRequest) (bool, error) {
+	err := q.rc.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: StreamKey(key),
+		Values: map[string]interface{}{streamField: proto.MarshalTextString(message)},
+	}).Err()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (q *streamsQueue) EnqueueBatch(ctx context.Context, entries []*BatchEntry, statusTTL time.Duration) []error {
+	return q.rc.pipelineBatch(ctx, entries, statusTTL, func(pipe redis.Pipeliner, entry *BatchEntry, value string) redis.Cmder {
+		if entry.SendAt.IsZero() {
+			return pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: StreamKey(QueueKey(entry.Message.GetPriority())),
+				Values: map[string]interface{}{streamField: value},
+			})
+		}
+		return scheduleBatchEntry(ctx, pipe, entry, value)
+	})
+}
+
+func (q *streamsQueue) Schedule(ctx context.Context, message *protos.MessageRequest, sendAt time.Time) error {
+	return q.rc.Schedule(ctx, message, sendAt)
+}
+
+func (q *streamsQueue) PromoteDue(ctx context.Context, priority protos.Priority, now time.Time, limit int) (int, error) {
+	keys := []string{ScheduledKey(priority), StreamKey(QueueKey(priority))}
+	return streamPromoteScript.Run(ctx, q.rc.client, keys, toMillis(now), limit, streamField).Int()
+}
+
+func (q *streamsQueue) PopAny(ctx context.Context, keys ...string) (*protos.MessageRequest, error) {
+	lease, err := q.ReserveAny(ctx, 0, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	return lease.Message, wrapError(q.Ack(ctx, lease))
+}
+
+func (q *streamsQueue) ReserveAny(ctx context.Context, leaseFor time.Duration, keys ...string) (*Lease, error) {
+	for _, key := range keys {
+		lease, err := q.read(ctx, key, leaseFor, -1)
+		if err == redis.Nil {
+			continue
+		}
+		return lease, err
+	}
+
+	return nil, redis.Nil
+}
+
+func (q *streamsQueue) ReserveBlocking(ctx context.Context, key string, leaseFor, timeout time.Duration) (*Lease, error) {
+	return q.read(ctx, key, leaseFor, timeout)
+}
+
+func (q *streamsQueue) Ack(ctx context.Context, lease *Lease) error {
+	return streamAckScript.Run(ctx, q.rc.client, []string{StreamKey(lease.Queue)}, q.group, lease.ID).Err()
+}
+
+func (q *streamsQueue) Retry(ctx context.Context, lease *Lease, message *protos.MessageRequest, retryAt time.Time) error {
+	keys := []string{StreamKey(lease.Queue), ScheduledKey(message.GetPriority())}
+	return streamRetryScript.Run(ctx, q.rc.client, keys,
+		q.group, lease.ID, proto.MarshalTextString(message), toMillis(retryAt)).Err()
+}
+
+func (q *streamsQueue) DeadLetter(ctx context.Context, lease *Lease, deadLetter *protos.DeadLetter) error {
+	failedAt, err := ptypes.Timestamp(deadLetter.GetFailedAt())
+	if err != nil {
+		return err
+	}
+
+	keys := []string{StreamKey(lease.Queue), DeadLettersKey, DeadLettersIndexKey}
+	return streamDeadLetterScript.Run(ctx, q.rc.client, keys,
+		q.group, lease.ID, deadLetter.GetId(), proto.MarshalTextString(deadLetter), toMillis(failedAt)).Err()
+}
+
+// ReapExpired => messages pending for longer than leaseFor are added to the stream again
+// Redis tracks how long ago they were delivered, so now is not needed. At most reapBatchSize
+// oldest pending messages are checked per call.
+func (q *streamsQueue) ReapExpired(ctx context.Context, key string, _ time.Time, leaseFor time.Duration) (int, error) {
+	pending, err := q.rc.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: StreamKey(key),
+		Group:  q.group,
+		Start:  "-",
+		End:    "+",
+		Count:  reapBatchSize,
+	}).Result()
+	if isNoGroup(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, wrapError(err)
+	}
+
+	args := []interface{}{q.group, q.consumer, int64(leaseFor / time.Millisecond)}
+	for _, entry := range pending {
+		if entry.Idle >= leaseFor {
+			args = append(args, entry.ID)
+		}
+	}
+	if len(args) == 3 {
+		return 0, nil
+	}
+
+	return streamReapScript.Run(ctx, q.rc.client, []string{StreamKey(key)}, args...).Int()
+}
+
+func (q *streamsQueue) GetDeadLetter(ctx context.Context, id string) (*protos.DeadLetter, error) {
+	return q.rc.GetDeadLetter(ctx, id)
+}
+
+func (q *streamsQueue) ListDeadLetters(ctx context.Context, offset, limit int64) ([]*protos.DeadLetter, int64, error) {
+	return q.rc.ListDeadLetters(ctx, offset, limit)
+}
+
+func (q *streamsQueue) PurgeDeadLetters(ctx context.Context, ids ...string) (int64, error) {
+	return q.rc.PurgeDeadLetters(ctx, ids...)
+}
+
+func (q *streamsQueue) ReplayDeadLetter(ctx context.Context, id, queue string, message *protos.MessageRequest) (bool, error) {
+	keys := []string{DeadLettersKey, DeadLettersIndexKey, StreamKey(queue)}
+	replayed, err := streamReplayScript.Run(ctx, q.rc.client, keys, id, streamField, proto.MarshalTextString(message)).Int()
+
+	return replayed == 1, err
+}
+
+// QueueStats => ready messages are the ones in the stream which are not pending
+func (q *streamsQueue) QueueStats(ctx context.Context, priorities ...protos.Priority) ([]QueueStats, int64, error) {
+	type counts struct {
+		length, scheduled *redis.IntCmd
+		pending           *redis.XPendingCmd
+	}
+	cmds := make([]counts, len(priorities))
+	var deadLetters *redis.IntCmd
+	// pending fails for streams without a consumer group, so the pipeline error is not needed
+	_, _ = q.rc.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, priority := range priorities {
+			cmds[i].length = pipe.XLen(ctx, StreamKey(QueueKey(priority)))
+			cmds[i].pending = pipe.XPending(ctx, StreamKey(QueueKey(priority)), q.group)
+			cmds[i].scheduled = pipe.ZCard(ctx, ScheduledKey(priority))
+		}
+		deadLetters = pipe.ZCard(ctx, DeadLettersIndexKey)
+		return nil
+	})
+	if err := deadLetters.Err(); err != nil {
+		return nil, 0, wrapError(err)
+	}
+
+	stats := make([]QueueStats, len(priorities))
+	for i, priority := range priorities {
+		stats[i] = QueueStats{
+			Queue:     QueueKey(priority),
+			Ready:     cmds[i].length.Val(),
+			Scheduled: cmds[i].scheduled.Val(),
+		}
+		if pending, err := cmds[i].pending.Result(); err == nil {
+			stats[i].Processing = pending.Count
+			stats[i].Ready -= pending.Count
+		}
+	}
+
+	return stats, deadLetters.Val(), nil
+}
@@ -0,0 +1,207 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/ptypes"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func newTestStreamsQueue(t *testing.T) (*streamsQueue, *miniredis.Miniredis) {
+	rc, server := newTestRedis(t)
+	return newStreamsQueue(rc, "dispatchers", "worker-1"), server
+}
+
+// checkStream => compares ready and pending messages of stream of normal priority queue
+func checkStream(t *testing.T, q *streamsQueue, ready, processing, scheduled int64) {
+	t.Helper()
+	stats, _, err := q.QueueStats(context.Background(), protos.Priority_NORMAL)
+	if err != nil {
+		t.Fatalf("QueueStats() error = %v", err)
+	}
+	if stats[0].Ready != ready || stats[0].Processing != processing || stats[0].Scheduled != scheduled {
+		t.Errorf("stats = %+v, want %d ready, %d processing and %d scheduled", stats[0], ready, processing, scheduled)
+	}
+}
+
+func TestStreamsReserveAndAck(t *testing.T) {
+	q, server := newTestStreamsQueue(t)
+	ctx := context.Background()
+
+	// group is created on the first read, messages added before it are delivered too
+	if _, err := q.Push(ctx, NormalPriorityQueue, testMessage("m1", protos.Priority_NORMAL)); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	lease, err := q.ReserveAny(ctx, time.Minute, HighPriorityQueue, NormalPriorityQueue)
+	if err != nil || lease.Queue != NormalPriorityQueue || lease.Message.GetId() != "m1" {
+		t.Fatalf("ReserveAny() = %+v, %v, want m1 of normal queue", lease, err)
+	}
+	checkStream(t, q, 0, 1, 0)
+
+	// another consumer of the group does not get the reserved message
+	other := newStreamsQueue(q.rc, "dispatchers", "worker-2")
+	if _, err := other.ReserveAny(ctx, time.Minute, NormalPriorityQueue); err != redis.Nil {
+		t.Errorf("ReserveAny() of reserved message error = %v, want redis.Nil", err)
+	}
+
+	if err := q.Ack(ctx, lease); err != nil {
+		t.Fatalf("Ack() error = %v", err)
+	}
+	checkStream(t, q, 0, 0, 0)
+	if entries, _ := server.Stream(StreamKey(NormalPriorityQueue)); len(entries) != 0 {
+		t.Errorf("stream has %d entries after ack, want none", len(entries))
+	}
+}
+
+func TestStreamsGroupRecreated(t *testing.T) {
+	q, server := newTestStreamsQueue(t)
+	ctx := context.Background()
+
+	if err := q.ensureGroup(ctx, StreamKey(NormalPriorityQueue)); err != nil {
+		t.Fatalf("ensureGroup() error = %v", err)
+	}
+	// existing group is not an error
+	if err := q.ensureGroup(ctx, StreamKey(NormalPriorityQueue)); err != nil {
+		t.Fatalf("ensureGroup() of existing group error = %v", err)
+	}
+
+	// stream and its group are lost (eg: redis was flushed), group is known to exist by the queue
+	server.FlushAll()
+	q.Push(ctx, NormalPriorityQueue, testMessage("m1", protos.Priority_NORMAL))
+	lease, err := q.ReserveAny(ctx, time.Minute, NormalPriorityQueue)
+	if err != nil || lease.Message.GetId() != "m1" {
+		t.Fatalf("ReserveAny() after flush = %+v, %v, want m1", lease, err)
+	}
+
+	// reaping a stream without group finds nothing
+	if requeued, err := q.ReapExpired(ctx, HighPriorityQueue, time.Now(), time.Minute); err != nil || requeued != 0 {
+		t.Errorf("ReapExpired() without group = %d, %v, want 0", requeued, err)
+	}
+}
+
+func TestStreamsReapExpired(t *testing.T) {
+	q, server := newTestStreamsQueue(t)
+	ctx := context.Background()
+
+	q.Push(ctx, NormalPriorityQueue, testMessage("m1", protos.Priority_NORMAL))
+	q.Push(ctx, NormalPriorityQueue, testMessage("m2", protos.Priority_NORMAL))
+	expired, _ := q.ReserveAny(ctx, time.Minute, NormalPriorityQueue)
+	if requeued, err := q.ReapExpired(ctx, NormalPriorityQueue, time.Now(), time.Minute); err != nil || requeued != 0 {
+		t.Fatalf("ReapExpired() of fresh lease = %d, %v, want 0", requeued, err)
+	}
+
+	server.SetTime(time.Now().Add(2 * time.Minute))
+	active, _ := q.ReserveAny(ctx, time.Minute, NormalPriorityQueue)
+
+	// each reaper claims the expired entry, so it is requeued by only one of them
+	reaper := newStreamsQueue(q.rc, "dispatchers", "worker-2")
+	total := 0
+	for _, r := range []*streamsQueue{q, reaper, q} {
+		requeued, err := r.ReapExpired(ctx, NormalPriorityQueue, time.Now(), time.Minute)
+		if err != nil {
+			t.Fatalf("ReapExpired() error = %v", err)
+		}
+		total += requeued
+	}
+	if total != 1 {
+		t.Fatalf("requeued %d messages, want the expired one once", total)
+	}
+	checkStream(t, q, 1, 1, 0)
+
+	lease, err := q.ReserveAny(ctx, time.Minute, NormalPriorityQueue)
+	if err != nil || lease.Message.GetId() != expired.Message.GetId() || lease.ID == expired.ID {
+		t.Fatalf("ReserveAny() = %+v, %v, want %s added again", lease, err, expired.Message.GetId())
+	}
+
+	// acking the requeued entry and the active lease leaves nothing behind
+	for _, l := range []*Lease{lease, active} {
+		if err := q.Ack(ctx, l); err != nil {
+			t.Fatalf("Ack() error = %v", err)
+		}
+	}
+	checkStream(t, q, 0, 0, 0)
+}
+
+func TestStreamsReapAckedEntry(t *testing.T) {
+	q, server := newTestStreamsQueue(t)
+	ctx := context.Background()
+
+	q.Push(ctx, NormalPriorityQueue, testMessage("m1", protos.Priority_NORMAL))
+	lease, _ := q.ReserveAny(ctx, time.Minute, NormalPriorityQueue)
+	server.SetTime(time.Now().Add(2 * time.Minute))
+
+	// entry is deleted but still pending, eg: only XDEL of a slow worker went through
+	if err := q.rc.client.XDel(ctx, StreamKey(NormalPriorityQueue), lease.ID).Err(); err != nil {
+		t.Fatalf("XDel() error = %v", err)
+	}
+	requeued, err := q.ReapExpired(ctx, NormalPriorityQueue, time.Now(), time.Minute)
+	if err != nil || requeued != 0 {
+		t.Fatalf("ReapExpired() of deleted entry = %d, %v, want 0", requeued, err)
+	}
+	checkStream(t, q, 0, 0, 0)
+}
+
+func TestStreamsRetry(t *testing.T) {
+	q, _ := newTestStreamsQueue(t)
+	ctx := context.Background()
+
+	q.Push(ctx, NormalPriorityQueue, testMessage("m1", protos.Priority_NORMAL))
+	lease, _ := q.ReserveAny(ctx, time.Minute, NormalPriorityQueue)
+
+	lease.Message.Attempts = 1
+	retryAt := time.Now().Add(time.Minute)
+	if err := q.Retry(ctx, lease, lease.Message, retryAt); err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	checkStream(t, q, 0, 0, 1)
+
+	if promoted, err := q.PromoteDue(ctx, protos.Priority_NORMAL, time.Now(), 10); err != nil || promoted != 0 {
+		t.Fatalf("PromoteDue() before retry time = %d, %v, want 0", promoted, err)
+	}
+	if promoted, err := q.PromoteDue(ctx, protos.Priority_NORMAL, retryAt, 10); err != nil || promoted != 1 {
+		t.Fatalf("PromoteDue() = %d, %v, want 1", promoted, err)
+	}
+	retried, err := q.PopAny(ctx, NormalPriorityQueue)
+	if err != nil || retried.GetId() != "m1" || retried.GetAttempts() != 1 {
+		t.Errorf("retried message = %v, %v, want m1 with updated attempts", retried, err)
+	}
+	checkStream(t, q, 0, 0, 0)
+}
+
+func TestStreamsDeadLetterAndReplay(t *testing.T) {
+	q, _ := newTestStreamsQueue(t)
+	ctx := context.Background()
+
+	q.Push(ctx, NormalPriorityQueue, testMessage("m1", protos.Priority_NORMAL))
+	lease, _ := q.ReserveAny(ctx, time.Minute, NormalPriorityQueue)
+
+	deadLetter := &protos.DeadLetter{Id: "m1", Message: lease.Message, Error: "invalid address", FailedAt: ptypes.TimestampNow()}
+	if err := q.DeadLetter(ctx, lease, deadLetter); err != nil {
+		t.Fatalf("DeadLetter() error = %v", err)
+	}
+	checkStream(t, q, 0, 0, 0)
+	if stored, err := q.GetDeadLetter(ctx, "m1"); err != nil || stored.GetError() != "invalid address" {
+		t.Fatalf("GetDeadLetter() = %v, %v", stored, err)
+	}
+
+	replayed, err := q.ReplayDeadLetter(ctx, "m1", NormalPriorityQueue, deadLetter.GetMessage())
+	if err != nil || !replayed {
+		t.Fatalf("ReplayDeadLetter() = %v, %v, want replayed", replayed, err)
+	}
+	if replayed, err := q.ReplayDeadLetter(ctx, "m1", NormalPriorityQueue, deadLetter.GetMessage()); err != nil || replayed {
+		t.Errorf("ReplayDeadLetter() again = %v, %v, want not replayed twice", replayed, err)
+	}
+	if _, total, err := q.ListDeadLetters(ctx, 0, 10); err != nil || total != 0 {
+		t.Errorf("ListDeadLetters() total = %d, %v, want none after replay", total, err)
+	}
+
+	message, err := q.PopAny(ctx, NormalPriorityQueue)
+	if err != nil || message.GetId() != "m1" {
+		t.Errorf("replayed message = %v, %v, want m1", message, err)
+	}
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// SuppressionsKey => hash of suppression field (address, with category when scoped) => suppression
+const SuppressionsKey = "suppressions"
+
+// SuppressionsIndexKey => sorted set of suppression fields scored by creation time, of all categories
+// when category is empty, otherwise of the given category
+func SuppressionsIndexKey(category string) string {
+	if category == "" {
+		return "suppressions:index"
+	}
+	return "suppressions:index:" + category
+}
+
+// suppressionField => hash field of suppressed address, addresses are case insensitive
+func suppressionField(address, category string) string {
+	field := strings.ToLower(address)
+	if category != "" {
+		field += "/" + category
+	}
+	return field
+}
+
+// AddSuppression => suppresses address (for a category), existing suppression of address is replaced
+func (rc *Redis) AddSuppression(ctx context.Context, suppression *protos.Suppression) error {
+	createdAt, err := ptypes.Timestamp(suppression.GetCreatedAt())
+	if err != nil {
+		return err
+	}
+
+	field := suppressionField(suppression.GetAddress(), suppression.GetCategory())
+	member := &redis.Z{Score: float64(toMillis(createdAt)), Member: field}
+	_, err = rc.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, SuppressionsKey, field, proto.MarshalTextString(suppression))
+		pipe.ZAdd(ctx, SuppressionsIndexKey(""), member)
+		if suppression.GetCategory() != "" {
+			pipe.ZAdd(ctx, SuppressionsIndexKey(suppression.GetCategory()), member)
+		}
+		return nil
+	})
+
+	return err
+}
+
+// RemoveSuppression => removes suppression of address (for a category), returns false when there was none
+func (rc *Redis) RemoveSuppression(ctx context.Context, address, category string) (bool, error) {
+	field := suppressionField(address, category)
+
+	var removed *redis.IntCmd
+	_, err := rc.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		removed = pipe.HDel(ctx, SuppressionsKey, field)
+		pipe.ZRem(ctx, SuppressionsIndexKey(""), field)
+		if category != "" {
+			pipe.ZRem(ctx, SuppressionsIndexKey(category), field)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return removed.Val() > 0, nil
+}
+
+// ListSuppressions => returns suppressions newest first and their total, of all categories when category is empty
+func (rc *Redis) ListSuppressions(
+	ctx context.Context, category string, offset, limit int64) ([]*protos.Suppression, int64, error) {
+	index := SuppressionsIndexKey(category)
+	total, err := rc.client.ZCard(ctx, index).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	fields, err := rc.client.ZRevRange(ctx, index, offset, offset+limit-1).Result()
+	if err != nil || len(fields) == 0 {
+		return nil, total, err
+	}
+
+	values, err := rc.client.HMGet(ctx, SuppressionsKey, fields...).Result()
+	if err != nil {
+		return nil, total, err
+	}
+
+	suppressions := make([]*protos.Suppression, 0, len(values))
+	for _, value := range values {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var suppression protos.Suppression
+		if err := proto.UnmarshalText(text, &suppression); err != nil {
+			continue
+		}
+		suppressions = append(suppressions, &suppression)
+	}
+
+	return suppressions, total, nil
+}
+
+// SuppressedAddresses => returns which of the given addresses are suppressed for all categories or
+// for the given category, keyed by lower cased address
+func (rc *Redis) SuppressedAddresses(ctx context.Context, category string, addresses ...string) (map[string]bool, error) {
+	suppressed := map[string]bool{}
+	if len(addresses) == 0 {
+		return suppressed, nil
+	}
+
+	// field of every address and category, with the address it belongs to
+	var fields, owners []string
+	for _, address := range addresses {
+		address = strings.ToLower(address)
+		fields = append(fields, suppressionField(address, ""))
+		owners = append(owners, address)
+		if category != "" {
+			fields = append(fields, suppressionField(address, category))
+			owners = append(owners, address)
+		}
+	}
+
+	values, err := rc.client.HMGet(ctx, SuppressionsKey, fields...).Result()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	for i, value := range values {
+		if value != nil {
+			suppressed[owners[i]] = true
+		}
+	}
+
+	return suppressed, nil
+}
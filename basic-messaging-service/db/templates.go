@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/proto"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// TemplatesKey => set of template ids
+const TemplatesKey = "templates"
+
+// TemplateKey => hash of a template, latest version number and every version by its number
+func TemplateKey(id string) string {
+	return "template:" + id
+}
+
+// createTemplateScript stores first version of a template, nothing is stored when it already exists
+var createTemplateScript = redis.NewScript(`
+if redis.call('HSETNX', KEYS[1], 'latest', 1) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], '1', ARGV[2])
+redis.call('SADD', KEYS[2], ARGV[1])
+return 1
+`)
+
+// updateTemplateScript stores a new version of an existing template and returns its number
+var updateTemplateScript = redis.NewScript(`
+if redis.call('HEXISTS', KEYS[1], 'latest') == 0 then
+	return 0
+end
+local version = redis.call('HINCRBY', KEYS[1], 'latest', 1)
+redis.call('HSET', KEYS[1], tostring(version), ARGV[1])
+return version
+`)
+
+// getTemplateScript returns given version of a template, latest version when it is 0
+var getTemplateScript = redis.NewScript(`
+local version = ARGV[1]
+if version == '0' then
+	version = redis.call('HGET', KEYS[1], 'latest')
+	if not version then
+		return false
+	end
+end
+local template = redis.call('HGET', KEYS[1], version)
+if not template then
+	return false
+end
+return {version, template}
+`)
+
+// CreateTemplate => stores template as its first version, returns false when template already exists
+func (rc *Redis) CreateTemplate(ctx context.Context, template *protos.Template) (bool, error) {
+	template.Version = 1
+	created, err := createTemplateScript.Run(ctx, rc.client, []string{TemplateKey(template.GetId()), TemplatesKey},
+		template.GetId(), proto.MarshalTextString(template)).Int()
+
+	return created == 1, err
+}
+
+// UpdateTemplate => stores template as a new version, returns redis.Nil when template does not exist
+func (rc *Redis) UpdateTemplate(ctx context.Context, template *protos.Template) error {
+	// version is only known inside the script, stored version is set when reading it
+	template.Version = 0
+	version, err := updateTemplateScript.Run(ctx, rc.client, []string{TemplateKey(template.GetId())},
+		proto.MarshalTextString(template)).Int()
+	if err != nil {
+		return err
+	} else if version == 0 {
+		return redis.Nil
+	}
+
+	template.Version = int32(version)
+	return nil
+}
+
+// GetTemplate => returns given version of template, latest version when version is 0
+// Returns redis.Nil when template or version does not exist.
+func (rc *Redis) GetTemplate(ctx context.Context, id string, version int32) (*protos.Template, error) {
+	result, err := getTemplateScript.Run(ctx, rc.client, []string{TemplateKey(id)}, version).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	values, _ := result.([]interface{})
+	if len(values) != 2 {
+		return nil, &OperationError{operation: "get template"}
+	}
+	number, _ := values[0].(string)
+	text, _ := values[1].(string)
+
+	return parseTemplate(number, text)
+}
+
+// ListTemplates => returns latest version of every template, ordered by id
+func (rc *Redis) ListTemplates(ctx context.Context) ([]*protos.Template, error) {
+	ids, err := rc.client.SMembers(ctx, TemplatesKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+
+	templates := make([]*protos.Template, 0, len(ids))
+	for _, id := range ids {
+		template, err := rc.GetTemplate(ctx, id, 0)
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+func parseTemplate(version, text string) (*protos.Template, error) {
+	var template protos.Template
+	if err := proto.UnmarshalText(text, &template); err != nil {
+		return nil, &InvalidMessageError{Payload: text, Err: err}
+	}
+
+	number, err := strconv.Atoi(version)
+	if err != nil {
+		return nil, &InvalidMessageError{Payload: version, Err: err}
+	}
+	template.Version = int32(number)
+
+	return &template, nil
+}
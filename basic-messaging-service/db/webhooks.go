@@ -0,0 +1,21 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// WebhookEventKey => marks an event received from a provider webhook, providers may deliver an event more than once
+func WebhookEventKey(provider, id string) string {
+	return "webhook:" + provider + ":" + id
+}
+
+// ClaimWebhookEvent => returns true when event is seen for the first time in ttl
+func (rc *Redis) ClaimWebhookEvent(ctx context.Context, provider, id string, ttl time.Duration) (bool, error) {
+	return rc.client.SetNX(ctx, WebhookEventKey(provider, id), 1, ttl).Result()
+}
+
+// ReleaseWebhookEvent => forgets event, so it is handled again when provider retries it
+func (rc *Redis) ReleaseWebhookEvent(ctx context.Context, provider, id string) error {
+	return rc.client.Del(ctx, WebhookEventKey(provider, id)).Err()
+}
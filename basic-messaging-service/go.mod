@@ -3,17 +3,23 @@ module github.com/frost060/go-microservice-basic/basic-messaging-service
 go 1.14
 
 require (
+	github.com/alicebob/gopher-json v0.0.0-20200520072559-a9ecdc9d1d3a // indirect
+	github.com/alicebob/miniredis/v2 v2.37.0
 	github.com/dgryski/go-rendezvous v0.0.0-20200624174652-8d2f3be8b2d9 // indirect
 	github.com/go-redis/redis/v8 v8.0.0-beta.5
 	github.com/golang/protobuf v1.4.2
+	github.com/google/uuid v1.1.1
 	github.com/joho/godotenv v1.3.0
 	github.com/niemeyer/pretty v0.0.0-20200227124842-a10e7caefd8e // indirect
 	github.com/onsi/ginkgo v1.13.0 // indirect
+	github.com/prometheus/client_golang v1.7.1
 	github.com/sendgrid/rest v2.6.0+incompatible // indirect
 	github.com/sendgrid/sendgrid-go v3.6.0+incompatible
 	github.com/sirupsen/logrus v1.6.0
 	github.com/stretchr/testify v1.6.1 // indirect
-	go.opentelemetry.io/otel v0.7.0 // indirect
+	go.opentelemetry.io/otel v0.7.0
+	go.opentelemetry.io/otel/exporters/otlp v0.7.0
+	go.opentelemetry.io/otel/exporters/trace/jaeger v0.7.0
 	golang.org/x/net v0.0.0-20200625001655-4c5254603344 // indirect
 	golang.org/x/sys v0.0.0-20200625212154-ddb9806d33ae // indirect
 	golang.org/x/text v0.3.3 // indirect
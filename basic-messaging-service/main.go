@@ -1,18 +1,31 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/auth"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/certs"
 	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
 	"github.com/frost060/go-microservice-basic/basic-messaging-service/logging"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/metrics"
 	"github.com/joho/godotenv"
+	"go.opentelemetry.io/otel/instrumentation/grpctrace"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
 	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
 	"github.com/frost060/go-microservice-basic/basic-messaging-service/server"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/tracing"
 	"google.golang.org/grpc"
 )
 
@@ -28,28 +41,174 @@ func main() {
 
 	serverConfig := configs.NewConfig()
 
-	gs := grpc.NewServer()
+	stopTracing, err := tracing.Setup(serverConfig.Tracing)
+	if err != nil {
+		log.Error("Unable to setup tracing: %v", err)
+		os.Exit(1)
+	}
+	tracer := tracing.Tracer()
+
+	authenticator, err := auth.NewAuthenticator(serverConfig.Auth)
+	if err != nil {
+		log.Error("Invalid auth configs: %v", err)
+		os.Exit(1)
+	}
+	if !serverConfig.Auth.Enabled() {
+		log.Warn("AUTH_API_KEYS and AUTH_TOKEN_SECRET are not set, callers are not authenticated")
+	}
+
+	serverOptions := []grpc.ServerOption{
+		// attachments are sent within requests, so allow more than the default 4MB
+		grpc.MaxRecvMsgSize(serverConfig.Attachments.MaxTotalSize + 4<<20),
+		// rejected calls are traced and counted too
+		grpc.ChainUnaryInterceptor(grpctrace.UnaryServerInterceptor(tracer), metrics.UnaryServerInterceptor,
+			authenticator.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(grpctrace.StreamServerInterceptor(tracer), metrics.StreamServerInterceptor,
+			authenticator.StreamServerInterceptor),
+	}
+
+	if err := serverConfig.SMTP.Validate(); err != nil {
+		log.Error("Invalid smtp configs: %v", err)
+		os.Exit(1)
+	}
+	if err := serverConfig.TLS.Validate(); err != nil {
+		log.Error("Invalid tls configs: %v", err)
+		os.Exit(1)
+	}
+	var reloader *certs.Reloader
+	if serverConfig.TLS.Enabled() {
+		reloader, err = certs.NewReloader(serverConfig.TLS, log)
+		if err != nil {
+			log.Error("Unable to load tls certificates: %v", err)
+			os.Exit(1)
+		}
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(reloader.ServerConfig())))
+		if serverConfig.TLS.ClientCAFile != "" {
+			log.Info("Client certificates are required (mutual TLS)")
+		}
+	} else {
+		log.Warn("TLS_CERT_FILE is not set, grpc requests are served without TLS")
+	}
+
+	gs := grpc.NewServer(serverOptions...)
 	log.Info("Created new grpc server...")
 
 	redis := db.NewRedisClient(serverConfig)
 
-	ms := server.NewMessageService(serverConfig, redis, log)
+	queue, err := db.NewQueue(serverConfig.Queue, redis)
+	if err != nil {
+		log.Error("Unable to create message queue: %v", err)
+		os.Exit(1)
+	}
+	log.Info("Queueing messages in %s backend", serverConfig.Queue.Backend)
+
+	ms := server.NewMessageService(serverConfig, redis, queue, log)
 	log.Info("Create new message service...")
 
+	// templates used by other services (eg: password reset mails) exist without a manual step
+	if err := ms.SeedTemplates(context.Background(), serverConfig.Templates.SeedDir); err != nil {
+		log.Error("Unable to seed templates: %v", err)
+	}
+
 	protos.RegisterNotificationServer(gs, ms)
 	log.Info("Successfully registered notification service")
 
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(gs, healthServer)
+	log.Info("Successfully registered health service")
+
 	reflection.Register(gs)
 
-	go ms.StartDispatchRedis(2, redis)
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatchDone := make(chan struct{})
+	go func() {
+		ms.StartDispatchRedis(ctx, 2, queue)
+		close(dispatchDone)
+	}()
+	if reloader != nil {
+		go reloader.Watch(ctx, serverConfig.TLS.ReloadInterval)
+	}
+	go ms.StartPromoter(ctx, serverConfig.Queue.PromoteInterval)
+	go ms.StartReaper(ctx, serverConfig.Queue.ReapInterval)
+	go ms.StartHealthChecks(ctx, healthServer)
+	go ms.StartMetricsSampler(ctx, serverConfig.Metrics.SampleInterval)
 
 	log.Info("Notification service running on port: 9092")
 	l, err := net.Listen("tcp", fmt.Sprintf(":%d", 9092))
 	if err != nil {
-		log.Error("Unable to create listener", "error", err)
+		log.Error("Unable to create listener: %v", err)
 		os.Exit(1)
 	}
 
 	// listen for requests
-	_ = gs.Serve(l)
+	go func() {
+		if err := gs.Serve(l); err != nil {
+			log.Error("Unable to start server: %v", err)
+			os.Exit(1)
+		}
+	}()
+
+	// provider webhooks and unsubscribe links
+	hs := &http.Server{Addr: serverConfig.HTTP.Addr, Handler: ms.HTTPHandler()}
+	go func() {
+		log.Info("HTTP server running on %s", serverConfig.HTTP.Addr)
+		if err := hs.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error("Unable to start http server: %v", err)
+			os.Exit(1)
+		}
+	}()
+
+	// metrics are not exposed on the public http server
+	var metricsServer *http.Server
+	if serverConfig.Metrics.Addr != "" {
+		metricsServer = &http.Server{Addr: serverConfig.Metrics.Addr, Handler: server.MetricsHandler()}
+		go func() {
+			log.Info("Metrics server running on %s", serverConfig.Metrics.Addr)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Error("Unable to start metrics server: %v", err)
+				os.Exit(1)
+			}
+		}()
+	} else {
+		log.Warn("METRICS_ADDR is not set, metrics are not served")
+	}
+
+	// trap sigterm or interupt and gracefully shutdown the server
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+
+	// Block until a signal is received.
+	sig := <-c
+	log.Info("Got signal: %v, shutting down...", sig)
+
+	// report not serving so no new requests are routed here, stop accepting requests,
+	// then let workers finish in-flight messages
+	healthServer.Shutdown()
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := hs.Shutdown(shutdownCtx); err != nil {
+		log.Error("Unable to stop http server: %v", err)
+	}
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			log.Error("Unable to stop metrics server: %v", err)
+		}
+	}
+	shutdownCancel()
+	// streams are ended first, graceful stop waits for every open rpc
+	ms.Stop()
+	grpcStopped := make(chan struct{})
+	go func() {
+		gs.GracefulStop()
+		close(grpcStopped)
+	}()
+	select {
+	case <-grpcStopped:
+	case <-time.After(10 * time.Second):
+		log.Warn("grpc requests did not finish in time, closing their connections")
+		gs.Stop()
+	}
+	cancel()
+	<-dispatchDone
+	stopTracing()
+	log.Info("Notification service stopped")
 }
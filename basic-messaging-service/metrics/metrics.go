@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "notifications"
+
+var (
+	// RPCs => handled grpc calls by full method and status code
+	RPCs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_total",
+		Help:      "Handled grpc calls by method and status code.",
+	}, []string{"method", "code"})
+
+	// RPCDuration => handling time of grpc calls, streams are observed when they end
+	RPCDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "Handling time of grpc calls by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	// ProviderDispatches => dispatch attempts of providers by outcome (see notifications.Outcome*)
+	ProviderDispatches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_dispatches_total",
+		Help:      "Dispatches through providers by outcome, skipped providers are counted as circuit_open or rate_limited.",
+	}, []string{"provider", "outcome"})
+
+	// ProviderDuration => time taken by providers for a dispatch, skipped providers are not observed
+	ProviderDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "provider_dispatch_duration_seconds",
+		Help:      "Time taken by providers to accept or reject a message.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"provider", "outcome"})
+
+	// BreakerState => state of provider circuit breakers (0 closed, 1 open, 2 half-open), sampled
+	BreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "provider_breaker_state",
+		Help:      "Circuit breaker state of providers, 0 closed, 1 open, 2 half-open.",
+	}, []string{"provider"})
+
+	// MessageStates => delivery state changes of messages by type
+	MessageStates = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "message_states_total",
+		Help:      "Delivery state changes of messages by type and state.",
+	}, []string{"type", "state"})
+
+	// Workers => size of the dispatch worker pool
+	Workers = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "workers",
+		Help:      "Number of dispatch workers.",
+	})
+
+	// BusyWorkers => workers reserving or dispatching a message, includes workers waiting on an empty queue
+	BusyWorkers = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "workers_busy",
+		Help:      "Number of dispatch workers out of the pool.",
+	})
+
+	// QueueMessages => messages of priority queues by stage (ready, processing, scheduled), sampled
+	QueueMessages = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_messages",
+		Help:      "Messages in priority queues by stage.",
+	}, []string{"queue", "stage"})
+
+	// DeadLetters => messages in dead letters, sampled
+	DeadLetters = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "dead_letters",
+		Help:      "Messages in dead letters.",
+	})
+)
+
+// DispatchObserver => records provider dispatches of failover (implements notifications.Observer)
+type DispatchObserver struct{}
+
+// ObserveDispatch => counts dispatch by outcome, duration of skipped providers is not observed
+func (DispatchObserver) ObserveDispatch(provider, outcome string, duration time.Duration) {
+	ProviderDispatches.WithLabelValues(provider, outcome).Inc()
+	if duration > 0 {
+		ProviderDuration.WithLabelValues(provider, outcome).Observe(duration.Seconds())
+	}
+}
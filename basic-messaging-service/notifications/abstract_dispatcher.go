@@ -3,3 +3,9 @@ package notifications
 type Dispatcher interface {
 	Dispatch() (bool, error)
 }
+
+// Responder => implemented by dispatchers which can tell what provider responded
+// to the last Dispatch (provider message id, status, etc)
+type Responder interface {
+	Response() string
+}
@@ -0,0 +1,134 @@
+package notifications
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerState => state of a provider circuit breaker
+type BreakerState int
+
+// Circuit breaker states
+const (
+	// BreakerClosed => provider is healthy, all requests are allowed
+	BreakerClosed BreakerState = iota
+	// BreakerOpen => provider is failing, requests are rejected until open timeout passes
+	BreakerOpen
+	// BreakerHalfOpen => open timeout passed, a single probe request is allowed
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker => tracks consecutive failures of a provider
+// Opens after failureThreshold consecutive failures, after openTimeout one probe
+// is let through (half-open). Probe success closes the breaker, failure opens it again.
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	name             string
+	failureThreshold int
+	openTimeout      time.Duration
+	state            BreakerState
+	failures         int
+	openedAt         time.Time
+	probing          bool
+}
+
+// NewCircuitBreaker => returns a new closed circuit breaker
+func NewCircuitBreaker(name string, failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+
+	return &CircuitBreaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		state:            BreakerClosed,
+	}
+}
+
+// Name => provider name this breaker belongs to
+func (cb *CircuitBreaker) Name() string {
+	return cb.name
+}
+
+// Allow => reports whether a request can be sent to the provider
+// Every allowed request must be followed by Success, Failure or Release
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case BreakerOpen:
+		if time.Since(cb.openedAt) < cb.openTimeout {
+			return false
+		}
+		cb.state = BreakerHalfOpen
+		cb.probing = true
+		return true
+	case BreakerHalfOpen:
+		if cb.probing {
+			return false
+		}
+		cb.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Success => records a successful request, closes the breaker
+func (cb *CircuitBreaker) Success() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.state = BreakerClosed
+	cb.failures = 0
+	cb.probing = false
+}
+
+// Failure => records a failed request, opens the breaker when threshold is reached
+// or when the half-open probe fails
+func (cb *CircuitBreaker) Failure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures++
+	cb.probing = false
+	if cb.state == BreakerHalfOpen || cb.failures >= cb.failureThreshold {
+		cb.state = BreakerOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+// Release => allowed request was not sent (eg: rate limited), no outcome is recorded
+// and a half-open breaker lets the next request probe instead.
+func (cb *CircuitBreaker) Release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+}
+
+// State => current state of the breaker
+func (cb *CircuitBreaker) State() BreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == BreakerOpen && time.Since(cb.openedAt) >= cb.openTimeout {
+		return BreakerHalfOpen
+	}
+	return cb.state
+}
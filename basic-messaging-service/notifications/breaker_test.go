@@ -0,0 +1,101 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	cb := NewCircuitBreaker("sendgrid", 3, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if !cb.Allow() {
+			t.Fatalf("request %d rejected before threshold", i)
+		}
+		cb.Failure()
+	}
+	if cb.State() != BreakerClosed {
+		t.Fatalf("state = %v after 2 failures, want closed", cb.State())
+	}
+
+	cb.Allow()
+	cb.Failure()
+	if cb.State() != BreakerOpen {
+		t.Fatalf("state = %v after 3 failures, want open", cb.State())
+	}
+	if cb.Allow() {
+		t.Error("open breaker allowed a request")
+	}
+}
+
+func TestBreakerSuccessResetsFailures(t *testing.T) {
+	cb := NewCircuitBreaker("sendgrid", 2, time.Hour)
+
+	cb.Allow()
+	cb.Failure()
+	cb.Allow()
+	cb.Success()
+	cb.Allow()
+	cb.Failure()
+
+	if cb.State() != BreakerClosed {
+		t.Errorf("state = %v, failures are not consecutive so want closed", cb.State())
+	}
+}
+
+func TestBreakerHalfOpenProbe(t *testing.T) {
+	cb := NewCircuitBreaker("smtp", 1, 10*time.Millisecond)
+	cb.Allow()
+	cb.Failure()
+
+	time.Sleep(20 * time.Millisecond)
+	if cb.State() != BreakerHalfOpen {
+		t.Fatalf("state = %v after open timeout, want half-open", cb.State())
+	}
+	if !cb.Allow() {
+		t.Fatal("probe request rejected")
+	}
+	if cb.Allow() {
+		t.Fatal("second request allowed while probing")
+	}
+
+	// failed probe opens the breaker again
+	cb.Failure()
+	if cb.State() != BreakerOpen || cb.Allow() {
+		t.Fatalf("state = %v after failed probe, want open", cb.State())
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if !cb.Allow() {
+		t.Fatal("probe request rejected")
+	}
+	cb.Success()
+	if cb.State() != BreakerClosed || !cb.Allow() || !cb.Allow() {
+		t.Errorf("state = %v after successful probe, want closed", cb.State())
+	}
+}
+
+func TestBreakerReleasedProbe(t *testing.T) {
+	cb := NewCircuitBreaker("smtp", 1, 10*time.Millisecond)
+	cb.Allow()
+	cb.Failure()
+
+	time.Sleep(20 * time.Millisecond)
+	if !cb.Allow() {
+		t.Fatal("probe request rejected")
+	}
+	cb.Release()
+	if cb.State() != BreakerHalfOpen || !cb.Allow() {
+		t.Errorf("state = %v after released probe, want half-open with a new probe allowed", cb.State())
+	}
+}
+
+func TestBreakerThresholdAtLeastOne(t *testing.T) {
+	cb := NewCircuitBreaker("smtp", 0, time.Hour)
+	cb.Allow()
+	cb.Failure()
+
+	if cb.State() != BreakerOpen {
+		t.Errorf("state = %v, want open", cb.State())
+	}
+}
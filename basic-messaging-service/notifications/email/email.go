@@ -8,18 +8,66 @@ import (
 // Email Service Providers Ordinal
 const (
 	SendGrid = 0
+	Smtp     = 1
 )
 
 // Email Service Providers
 const (
 	SENDGRID = "sendgrid"
+	SMTP     = "smtp"
 )
 
+// Address => email address with optional display name
+type Address struct {
+	Name  string
+	Email string
+}
+
+// Attachment => file attached to email, inline attachments are referenced from html by their content id
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+	Inline      bool
+	ContentID   string
+}
+
+// Message => email to be sent, HTML is the content and Text its optional plain text alternative
+// Recipients are expected to be unique across To, Cc and Bcc. ID is our message id, passed to
+// providers which can report events of the message back.
+type Message struct {
+	ID      string
+	From    Address
+	To      []Address
+	Cc      []Address
+	Bcc     []Address
+	ReplyTo *Address
+	Headers map[string]string
+	Subject string
+	HTML    string
+	Text    string
+
+	Attachments []Attachment
+}
+
+// Recipients => envelope recipients of message (to, cc and bcc)
+func (m *Message) Recipients() []string {
+	var recipients []string
+	for _, list := range [][]Address{m.To, m.Cc, m.Bcc} {
+		for _, address := range list {
+			recipients = append(recipients, address.Email)
+		}
+	}
+	return recipients
+}
+
 // Dispatcher => Dispatcher Factory For all Email dispatcher
-func Dispatcher(sender int, to, subject, msg string, config *configs.ServerConfig) notifications.Dispatcher {
+func Dispatcher(sender int, message *Message, config *configs.ServerConfig) notifications.Dispatcher {
 	switch sender {
 	case SendGrid:
-		return NewSendGridDispatcher(to, subject, msg, config.SendGrid.APIKey)
+		return NewSendGridDispatcher(message, config.SendGrid.APIKey)
+	case Smtp:
+		return NewSMTPDispatcher(message, config.SMTP)
 	default:
 		return nil
 	}
@@ -30,6 +78,8 @@ func GetProvider(sender string) int {
 	switch sender {
 	case SENDGRID:
 		return SendGrid
+	case SMTP:
+		return Smtp
 	default:
 		return -1
 	}
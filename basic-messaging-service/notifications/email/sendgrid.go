@@ -1,15 +1,16 @@
 package email
 
 import (
+	"encoding/base64"
+	"fmt"
 	"net/http"
 
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
 )
 
-// FromAddress => Default Sendgrid from address
-const FromAddress = "test@test.com"
-
 // SendGridAPIUrl => Sennd API url
 const SendGridAPIUrl = "https://api.sendgrid.com"
 
@@ -18,52 +19,118 @@ const SendGridAPIEndpoint = "/v3/mail/send"
 
 // SendGridDispatcher , extending default dispatcher
 type SendGridDispatcher struct {
-	to      string
-	from    string
-	msg     string
-	subject string
-	APIKey  string
+	message  *Message
+	APIKey   string
+	response string
 }
 
 // NewSendGridDispatcher => returns a new send grid dispatcher instance
-func NewSendGridDispatcher(to, subject, msg, APIKey string) *SendGridDispatcher {
+func NewSendGridDispatcher(message *Message, APIKey string) *SendGridDispatcher {
 	return &SendGridDispatcher{
-		to:      to,
-		from:    FromAddress,
-		msg:     msg,
-		subject: subject,
+		message: message,
 		APIKey:  APIKey,
 	}
 }
 
 // Dispatch => Create payload and calls sendgrid API with given payload (Create & Send Email)
 func (sd *SendGridDispatcher) Dispatch() (bool, error) {
-	body := GetHTMLBody(sd.to, sd.from, sd.subject, sd.msg)
-	return SendMail(body, sd.APIKey)
+	body := GetHTMLBody(sd.message)
+	success, response, err := SendMail(body, sd.APIKey)
+	sd.response = response
+	return success, err
+}
+
+// Response => sendgrid response of last dispatch (status and sendgrid message id)
+func (sd *SendGridDispatcher) Response() string {
+	return sd.response
 }
 
 // GetHTMLBody => Create mail body from Sendgrid
-func GetHTMLBody(toAddress, fromAddress, subject, contentHTML string) []byte {
-	from := mail.NewEmail("Test User", fromAddress)
-	to := mail.NewEmail("", toAddress)
-	content := mail.NewContent("text/html", contentHTML)
-	m := mail.NewV3MailInit(from, subject, to, content)
+// Plain text content is derived from html when not given, sendgrid requires it to come before html content.
+func GetHTMLBody(message *Message) []byte {
+	m := mail.NewV3Mail()
+	m.SetFrom(sendGridEmail(message.From))
+	m.Subject = message.Subject
+	if message.ReplyTo != nil {
+		m.SetReplyTo(sendGridEmail(*message.ReplyTo))
+	}
+	for key, value := range message.Headers {
+		m.SetHeader(key, value)
+	}
+	if message.ID != "" {
+		m.SetCustomArg(MessageIDArg, message.ID)
+	}
+
+	p := mail.NewPersonalization()
+	p.AddTos(sendGridEmails(message.To)...)
+	p.AddCCs(sendGridEmails(message.Cc)...)
+	p.AddBCCs(sendGridEmails(message.Bcc)...)
+	m.AddPersonalizations(p)
+
+	text := message.Text
+	if text == "" {
+		text = HTMLToText(message.HTML)
+	}
+	if text != "" {
+		m.AddContent(mail.NewContent("text/plain", text))
+	}
+	if message.HTML != "" {
+		m.AddContent(mail.NewContent("text/html", message.HTML))
+	}
+
+	for _, attachment := range message.Attachments {
+		a := mail.NewAttachment().
+			SetFilename(attachment.Filename).
+			SetType(attachment.ContentType).
+			SetContent(base64.StdEncoding.EncodeToString(attachment.Content))
+		if attachment.Inline {
+			a.SetDisposition("inline").SetContentID(attachment.ContentID)
+		} else {
+			a.SetDisposition("attachment")
+		}
+		m.AddAttachment(a)
+	}
 
 	return mail.GetRequestBody(m)
 }
 
+func sendGridEmail(address Address) *mail.Email {
+	return mail.NewEmail(address.Name, address.Email)
+}
+
+func sendGridEmails(addresses []Address) []*mail.Email {
+	emails := make([]*mail.Email, 0, len(addresses))
+	for _, address := range addresses {
+		emails = append(emails, sendGridEmail(address))
+	}
+	return emails
+}
+
 // SendMail => calls SendGrid API (Sends Mail)
-func SendMail(body []byte, apiKey string) (bool, error) {
+// Also returns response summary, status code and sendgrid message id
+func SendMail(body []byte, apiKey string) (bool, string, error) {
 	request := sendgrid.GetRequest(apiKey, SendGridAPIEndpoint, SendGridAPIUrl)
 	request.Method = http.MethodPost
 	request.Body = body
 	response, err := sendgrid.API(request)
 	if err != nil {
-		return false, err
+		return false, "", err
+	}
+
+	summary := fmt.Sprintf("status %d", response.StatusCode)
+	if ids := response.Headers["X-Message-Id"]; len(ids) > 0 {
+		summary += ", message id " + ids[0]
 	}
 
 	// https://sendgrid.com/docs/API_Reference/Web_API_v3/Mail/errors.html
 	// Sendgrid status codes.
-	return response.StatusCode == http.StatusAccepted ||
-		response.StatusCode == http.StatusOK, nil
+	if response.StatusCode == http.StatusAccepted || response.StatusCode == http.StatusOK {
+		return true, summary, nil
+	}
+
+	err = fmt.Errorf("sendgrid returned status %d: %s", response.StatusCode, response.Body)
+	if notifications.IsPermanentStatus(response.StatusCode) {
+		return false, summary, notifications.NewPermanentError(err)
+	}
+	return false, summary, err
 }
@@ -0,0 +1,106 @@
+package email
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// SendGrid signed event webhook headers
+const (
+	SendGridSignatureHeader = "X-Twilio-Email-Event-Webhook-Signature"
+	SendGridTimestampHeader = "X-Twilio-Email-Event-Webhook-Timestamp"
+)
+
+// MessageIDArg => custom arg holding our message id, sendgrid returns it with every event of the message
+const MessageIDArg = "message_id"
+
+// SendGrid event types
+// https://docs.sendgrid.com/for-developers/tracking-events/event
+const (
+	EventProcessed   = "processed"
+	EventDelivered   = "delivered"
+	EventDeferred    = "deferred"
+	EventBounce      = "bounce"
+	EventDropped     = "dropped"
+	EventOpen        = "open"
+	EventClick       = "click"
+	EventSpamReport  = "spamreport"
+	EventUnsubscribe = "unsubscribe"
+)
+
+// BounceTypeBounce => bounce event type of a hard bounce, soft bounces are "blocked"
+const BounceTypeBounce = "bounce"
+
+// WebhookEvent => event posted by sendgrid event webhook
+type WebhookEvent struct {
+	Email       string `json:"email"`
+	Timestamp   int64  `json:"timestamp"`
+	Event       string `json:"event"`
+	EventID     string `json:"sg_event_id"`
+	SGMessageID string `json:"sg_message_id"`
+	MessageID   string `json:"message_id"`
+	Reason      string `json:"reason"`
+	Response    string `json:"response"`
+	Type        string `json:"type"`
+	URL         string `json:"url"`
+}
+
+// ErrInvalidSignature => webhook request is not signed by sendgrid (or it is too old)
+var ErrInvalidSignature = errors.New("invalid sendgrid webhook signature")
+
+// ParseWebhookKey => parses verification key of signed event webhook (base64 of a DER public key)
+func ParseWebhookKey(key string) (*ecdsa.PublicKey, error) {
+	der, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, ok := parsed.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("sendgrid webhook verification key is not an ecdsa key")
+	}
+	return publicKey, nil
+}
+
+// VerifyWebhookSignature => verifies signature of timestamp + payload, signed less than tolerance ago
+func VerifyWebhookSignature(key *ecdsa.PublicKey, signature, timestamp string, payload []byte, tolerance time.Duration) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+
+	der, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	var sig struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return ErrInvalidSignature
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(timestamp))
+	hash.Write(payload)
+	if !ecdsa.Verify(key, hash.Sum(nil), sig.R, sig.S) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
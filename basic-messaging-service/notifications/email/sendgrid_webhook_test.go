@@ -0,0 +1,106 @@
+package email
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newWebhookKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		t.Fatalf("unable to marshal key: %v", err)
+	}
+	return private, base64.StdEncoding.EncodeToString(der)
+}
+
+// sign => signature sendgrid sends of timestamp + payload
+func sign(t *testing.T, private *ecdsa.PrivateKey, timestamp string, payload []byte) string {
+	hash := sha256.Sum256(append([]byte(timestamp), payload...))
+	r, s, err := ecdsa.Sign(rand.Reader, private, hash[:])
+	if err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		t.Fatalf("unable to marshal signature: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	private, encoded := newWebhookKey(t)
+	key, err := ParseWebhookKey(encoded)
+	if err != nil {
+		t.Fatalf("ParseWebhookKey() error = %v", err)
+	}
+	other, _ := newWebhookKey(t)
+
+	payload := []byte(`[{"email":"user@example.com","event":"delivered","message_id":"1"}]`)
+	seconds := time.Now().Unix()
+	now := strconv.FormatInt(seconds, 10)
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	future := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		signature string
+		timestamp string
+		payload   []byte
+		valid     bool
+	}{
+		{"valid", sign(t, private, now, payload), now, payload, true},
+		{"tampered payload", sign(t, private, now, payload), now, []byte(`[]`), false},
+		{"tampered timestamp", sign(t, private, now, payload), strconv.FormatInt(seconds-1, 10), payload, false},
+		{"other key", sign(t, other, now, payload), now, payload, false},
+		{"too old", sign(t, private, old, payload), old, payload, false},
+		{"in future", sign(t, private, future, payload), future, payload, false},
+		{"invalid timestamp", sign(t, private, "now", payload), "now", payload, false},
+		{"not base64", "not a signature", now, payload, false},
+		{"not asn1", base64.StdEncoding.EncodeToString([]byte("signature")), now, payload, false},
+		{"no signature", "", now, payload, false},
+	}
+
+	for _, test := range tests {
+		err := VerifyWebhookSignature(key, test.signature, test.timestamp, test.payload, 5*time.Minute)
+		if test.valid && err != nil {
+			t.Errorf("%s: VerifyWebhookSignature() error = %v", test.name, err)
+		} else if !test.valid && err != ErrInvalidSignature {
+			t.Errorf("%s: VerifyWebhookSignature() error = %v, want ErrInvalidSignature", test.name, err)
+		}
+	}
+}
+
+func TestParseWebhookKeyRejectsOtherKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatalf("unable to marshal key: %v", err)
+	}
+
+	for name, key := range map[string]string{
+		"rsa key":    base64.StdEncoding.EncodeToString(der),
+		"not base64": "not a key",
+		"not der":    base64.StdEncoding.EncodeToString([]byte("key")),
+	} {
+		if _, err := ParseWebhookKey(key); err == nil {
+			t.Errorf("%s: ParseWebhookKey() accepted the key", name)
+		}
+	}
+}
@@ -0,0 +1,408 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+)
+
+// SMTP encryption modes
+const (
+	SMTPEncryptionNone     = "none"
+	SMTPEncryptionStartTLS = "starttls"
+	SMTPEncryptionTLS      = "tls"
+)
+
+// SMTP auth mechanisms
+const (
+	SMTPAuthNone  = "none"
+	SMTPAuthPlain = "plain"
+	SMTPAuthLogin = "login"
+)
+
+// SMTPTimeout => max time for a single smtp session (dial + send)
+const SMTPTimeout = 30 * time.Second
+
+// smtpRootCAs => roots relay certificates are verified with, nil uses the system roots
+var smtpRootCAs *x509.CertPool
+
+// SMTPDispatcher , extending default dispatcher
+type SMTPDispatcher struct {
+	message   *Message
+	config    *configs.SMTPConfig
+	messageID string
+}
+
+// NewSMTPDispatcher => returns a new smtp dispatcher instance
+func NewSMTPDispatcher(message *Message, config *configs.SMTPConfig) *SMTPDispatcher {
+	return &SMTPDispatcher{
+		message: message,
+		config:  config,
+	}
+}
+
+// Dispatch => Create MIME message and sends it through configured smtp relay
+func (sd *SMTPDispatcher) Dispatch() (bool, error) {
+	sd.messageID = newMessageID(sd.message.From.Email)
+	body, err := GetMIMEBody(sd.message, sd.messageID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := SendSMTPMail(sd.config, sd.message.From.Email, sd.message.Recipients(), body); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Response => Message-ID of last dispatched mail, relays do not return anything else useful
+func (sd *SMTPDispatcher) Response() string {
+	if sd.messageID == "" {
+		return ""
+	}
+	return "message id " + sd.messageID
+}
+
+// GetMIMEBody => Create multipart/alternative mail body with plain text and html parts
+// Plain text part is derived from html when it is not given, html part is skipped for text only emails.
+// Bcc recipients are not added to headers.
+func GetMIMEBody(message *Message, messageID string) ([]byte, error) {
+	content, err := mimeContent(message)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := []string{
+		"From: " + mimeAddress(message.From),
+		"To: " + mimeAddressList(message.To),
+	}
+	if len(message.Cc) > 0 {
+		headers = append(headers, "Cc: "+mimeAddressList(message.Cc))
+	}
+	if message.ReplyTo != nil {
+		headers = append(headers, "Reply-To: "+mimeAddress(*message.ReplyTo))
+	}
+	headers = append(headers,
+		"Subject: "+mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date: "+time.Now().Format(time.RFC1123Z),
+		"Message-ID: "+messageID,
+	)
+	for key, value := range message.Headers {
+		headers = append(headers, textproto.CanonicalMIMEHeaderKey(key)+": "+mime.QEncoding.Encode("utf-8", value))
+	}
+	headers = append(headers,
+		"MIME-Version: 1.0",
+		"Content-Type: "+content.header.Get("Content-Type"),
+	)
+
+	var buf bytes.Buffer
+	buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+	buf.Write(content.body)
+
+	return buf.Bytes(), nil
+}
+
+// mimePart => headers and encoded body of a part of a multipart body
+type mimePart struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+// mimeContent => multipart/alternative of text and html parts, which is wrapped in multipart/related
+// with inline attachments and in multipart/mixed with other attachments when there are any
+func mimeContent(message *Message) (*mimePart, error) {
+	contentText := message.Text
+	if contentText == "" {
+		contentText = HTMLToText(message.HTML)
+	}
+
+	parts := []*mimePart{textPart("text/plain; charset=utf-8", contentText)}
+	if message.HTML != "" {
+		parts = append(parts, textPart("text/html; charset=utf-8", message.HTML))
+	}
+	content, err := multipartPart("alternative", parts)
+	if err != nil {
+		return nil, err
+	}
+
+	var inline, attached []*mimePart
+	for _, attachment := range message.Attachments {
+		if attachment.Inline {
+			inline = append(inline, attachmentPart(attachment))
+		} else {
+			attached = append(attached, attachmentPart(attachment))
+		}
+	}
+
+	if len(inline) > 0 {
+		if content, err = multipartPart("related", append([]*mimePart{content}, inline...)); err != nil {
+			return nil, err
+		}
+	}
+	if len(attached) > 0 {
+		if content, err = multipartPart("mixed", append([]*mimePart{content}, attached...)); err != nil {
+			return nil, err
+		}
+	}
+
+	return content, nil
+}
+
+func multipartPart(subtype string, parts []*mimePart) (*mimePart, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for _, part := range parts {
+		pw, err := writer.CreatePart(part.header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := pw.Write(part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return &mimePart{
+		header: textproto.MIMEHeader{"Content-Type": {"multipart/" + subtype + "; boundary=" + writer.Boundary()}},
+		body:   body.Bytes(),
+	}, nil
+}
+
+func textPart(contentType, content string) *mimePart {
+	var body bytes.Buffer
+	qp := quotedprintable.NewWriter(&body)
+	// writes to a bytes.Buffer cannot fail
+	_, _ = qp.Write([]byte(content))
+	_ = qp.Close()
+
+	return &mimePart{
+		header: textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		},
+		body: body.Bytes(),
+	}
+}
+
+// attachmentPart => base64 encoded attachment, lines are wrapped at 76 characters
+func attachmentPart(attachment Attachment) *mimePart {
+	contentType := mime.FormatMediaType(attachment.ContentType, map[string]string{"name": attachment.Filename})
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	disposition := "attachment"
+	header := textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+	}
+	if attachment.Inline {
+		disposition = "inline"
+		header.Set("Content-Id", "<"+attachment.ContentID+">")
+	}
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+
+	encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+	var body bytes.Buffer
+	for len(encoded) > 76 {
+		body.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	body.WriteString(encoded)
+
+	return &mimePart{header: header, body: body.Bytes()}
+}
+
+func mimeAddress(address Address) string {
+	return (&mail.Address{Name: address.Name, Address: address.Email}).String()
+}
+
+func mimeAddressList(addresses []Address) string {
+	list := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		list = append(list, mimeAddress(address))
+	}
+	return strings.Join(list, ", ")
+}
+
+// SendSMTPMail => opens a session with smtp relay and sends the given message
+func SendSMTPMail(config *configs.SMTPConfig, from string, to []string, body []byte) error {
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	tlsConfig := &tls.Config{ServerName: config.Host, RootCAs: smtpRootCAs}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	var conn net.Conn
+	var err error
+	if config.Encryption == SMTPEncryptionTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(SMTPTimeout))
+
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if config.Encryption == SMTPEncryptionStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	auth, err := smtpAuth(config)
+	if err != nil {
+		return err
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support AUTH")
+		}
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			// 5xx on RCPT => mailbox does not exist or is not accepted by relay
+			if protoErr, ok := err.(*textproto.Error); ok && protoErr.Code >= 500 {
+				return notifications.NewPermanentError(err)
+			}
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// smtpAuth => returns auth mechanism for given config, nil if auth is disabled
+// Unknown mechanism fails, so a misconfigured relay is never used without authentication.
+func smtpAuth(config *configs.SMTPConfig) (smtp.Auth, error) {
+	if config.Username == "" {
+		return nil, nil
+	}
+
+	switch config.Auth {
+	case SMTPAuthNone:
+		return nil, nil
+	case SMTPAuthPlain:
+		return smtp.PlainAuth("", config.Username, config.Password, config.Host), nil
+	case SMTPAuthLogin:
+		return &loginAuth{username: config.Username, password: config.Password}, nil
+	default:
+		return nil, fmt.Errorf("unknown smtp auth %q", config.Auth)
+	}
+}
+
+// loginAuth => implements AUTH LOGIN, which is not provided by net/smtp
+type loginAuth struct {
+	username string
+	password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected server challenge: %s", fromServer)
+	}
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
+
+func newMessageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at != -1 {
+		domain = from[at+1:]
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
+
+var (
+	blockTagsRegex = regexp.MustCompile(`(?i)<\s*(br|/p|/div|/h[1-6]|/li|/tr)\s*/?>`)
+	dropTagsRegex  = regexp.MustCompile(`(?is)<(style|script|head)[^>]*>.*?</(style|script|head)>`)
+	tagsRegex      = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankRegex     = regexp.MustCompile(`\n\s*\n+`)
+)
+
+// HTMLToText => Creates a plain text alternative from html content
+func HTMLToText(contentHTML string) string {
+	text := dropTagsRegex.ReplaceAllString(contentHTML, "")
+	text = blockTagsRegex.ReplaceAllString(text, "\n")
+	text = tagsRegex.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+
+	return strings.TrimSpace(blankRegex.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
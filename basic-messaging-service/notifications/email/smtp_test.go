@@ -0,0 +1,353 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io/ioutil"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+)
+
+// sinkMail => what a smtp sink received in a session
+type sinkMail struct {
+	tls        bool
+	mechanism  string
+	username   string
+	password   string
+	from       string
+	recipients []string
+	data       []byte
+}
+
+// smtpSink => smtp server accepting a single session, rejects recipients in reject with 550
+type smtpSink struct {
+	t         *testing.T
+	listener  net.Listener
+	tlsConfig *tls.Config
+	reject    map[string]bool
+	mails     chan *sinkMail
+}
+
+// newSMTPSink => local smtp server offering STARTTLS and AUTH PLAIN LOGIN, its certificate is trusted
+// by the smtp dispatcher until the test ends. Sessions are served in implicit TLS when implicitTLS is set.
+func newSMTPSink(t *testing.T, implicitTLS bool) (*smtpSink, *configs.SMTPConfig) {
+	certificate, pool := newSinkCertificate(t)
+	smtpRootCAs = pool
+	t.Cleanup(func() { smtpRootCAs = nil })
+
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{certificate}}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	if implicitTLS {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	sink := &smtpSink{t: t, listener: listener, tlsConfig: tlsConfig, reject: map[string]bool{}, mails: make(chan *sinkMail, 1)}
+	go sink.serve(implicitTLS)
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	return sink, &configs.SMTPConfig{Host: "127.0.0.1", Port: port, Encryption: SMTPEncryptionNone, Auth: SMTPAuthNone}
+}
+
+func newSinkCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %v", err)
+	}
+	parsed, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("unable to parse certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(parsed)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+func (s *smtpSink) serve(implicitTLS bool) {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	session := &sinkMail{tls: implicitTLS}
+	text := textproto.NewConn(conn)
+	reply := func(format string, args ...interface{}) {
+		if err := text.PrintfLine(format, args...); err != nil {
+			s.t.Errorf("sink unable to reply: %v", err)
+		}
+	}
+
+	reply("220 sink ready")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg := line, ""
+		if i := strings.IndexByte(line, ' '); i != -1 {
+			verb, arg = line[:i], line[i+1:]
+		}
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			if session.tls {
+				reply("250-sink\r\n250 AUTH PLAIN LOGIN")
+			} else {
+				reply("250-sink\r\n250-STARTTLS\r\n250 AUTH PLAIN LOGIN")
+			}
+		case "STARTTLS":
+			reply("220 go ahead")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				s.t.Errorf("sink tls handshake failed: %v", err)
+				return
+			}
+			conn, session.tls = tlsConn, true
+			text = textproto.NewConn(conn)
+		case "AUTH":
+			fields := strings.Fields(arg)
+			session.mechanism = strings.ToUpper(fields[0])
+			if session.mechanism == "PLAIN" {
+				// \x00username\x00password
+				decoded, _ := base64.StdEncoding.DecodeString(fields[1])
+				if parts := strings.Split(string(decoded), "\x00"); len(parts) == 3 {
+					session.username, session.password = parts[1], parts[2]
+				}
+			} else {
+				reply("334 %s", base64.StdEncoding.EncodeToString([]byte("Username:")))
+				session.username = s.readBase64(text)
+				reply("334 %s", base64.StdEncoding.EncodeToString([]byte("Password:")))
+				session.password = s.readBase64(text)
+			}
+			reply("235 authenticated")
+		case "MAIL":
+			session.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			reply("250 ok")
+		case "RCPT":
+			recipient := strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>")
+			if s.reject[recipient] {
+				reply("550 no such mailbox")
+				continue
+			}
+			session.recipients = append(session.recipients, recipient)
+			reply("250 ok")
+		case "DATA":
+			reply("354 send data")
+			session.data, err = ioutil.ReadAll(text.DotReader())
+			if err != nil {
+				return
+			}
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			s.mails <- session
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func (s *smtpSink) readBase64(text *textproto.Conn) string {
+	line, _ := text.ReadLine()
+	decoded, _ := base64.StdEncoding.DecodeString(line)
+	return string(decoded)
+}
+
+func (s *smtpSink) received() *sinkMail {
+	select {
+	case mail := <-s.mails:
+		return mail
+	case <-time.After(5 * time.Second):
+		s.t.Fatal("sink did not receive a mail")
+		return nil
+	}
+}
+
+func TestSendSMTPMailSessions(t *testing.T) {
+	tests := []struct {
+		name        string
+		implicitTLS bool
+		encryption  string
+		auth        string
+		mechanism   string
+	}{
+		{"starttls with plain auth", false, SMTPEncryptionStartTLS, SMTPAuthPlain, "PLAIN"},
+		{"starttls with login auth", false, SMTPEncryptionStartTLS, SMTPAuthLogin, "LOGIN"},
+		{"implicit tls with plain auth", true, SMTPEncryptionTLS, SMTPAuthPlain, "PLAIN"},
+		{"no encryption without auth", false, SMTPEncryptionNone, SMTPAuthNone, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sink, config := newSMTPSink(t, test.implicitTLS)
+			config.Encryption, config.Auth = test.encryption, test.auth
+			config.Username, config.Password = "relay-user", "relay-password"
+
+			err := SendSMTPMail(config, "noreply@example.com", []string{"user@example.com", "copy@example.com"}, []byte("Subject: hi\r\n\r\nhello\r\n"))
+			if err != nil {
+				t.Fatalf("SendSMTPMail() error = %v", err)
+			}
+
+			session := sink.received()
+			if session.tls != (test.encryption != SMTPEncryptionNone) {
+				t.Errorf("session tls = %v, want %v", session.tls, !session.tls)
+			}
+			if session.mechanism != test.mechanism {
+				t.Errorf("auth mechanism = %q, want %q", session.mechanism, test.mechanism)
+			}
+			if test.mechanism != "" && (session.username != "relay-user" || session.password != "relay-password") {
+				t.Errorf("credentials = %q, %q", session.username, session.password)
+			}
+			if session.from != "noreply@example.com" || strings.Join(session.recipients, ",") != "user@example.com,copy@example.com" {
+				t.Errorf("envelope = %s to %v", session.from, session.recipients)
+			}
+			if string(session.data) != "Subject: hi\n\nhello\n" {
+				t.Errorf("data = %q", session.data)
+			}
+		})
+	}
+}
+
+func TestSendSMTPMailErrors(t *testing.T) {
+	t.Run("rejected recipient is permanent", func(t *testing.T) {
+		sink, config := newSMTPSink(t, false)
+		sink.reject["missing@example.com"] = true
+
+		err := SendSMTPMail(config, "noreply@example.com", []string{"missing@example.com"}, []byte("hello\r\n"))
+		if err == nil || !notifications.IsPermanent(err) {
+			t.Errorf("SendSMTPMail() error = %v, want permanent error", err)
+		}
+	})
+
+	t.Run("unknown auth is not skipped", func(t *testing.T) {
+		_, config := newSMTPSink(t, false)
+		config.Encryption, config.Auth, config.Username = SMTPEncryptionStartTLS, "plian", "relay-user"
+
+		err := SendSMTPMail(config, "noreply@example.com", []string{"user@example.com"}, []byte("hello\r\n"))
+		if err == nil || !strings.Contains(err.Error(), "unknown smtp auth") {
+			t.Errorf("SendSMTPMail() error = %v, want unknown auth", err)
+		}
+	})
+}
+
+func TestSMTPDispatchMIMEBody(t *testing.T) {
+	sink, config := newSMTPSink(t, false)
+	message := &Message{
+		From:    Address{Name: "Service", Email: "noreply@example.com"},
+		To:      []Address{{Name: "User", Email: "user@example.com"}},
+		Cc:      []Address{{Email: "copy@example.com"}},
+		Bcc:     []Address{{Email: "hidden@example.com"}},
+		Subject: "Your report",
+		HTML:    "<p>Hello <b>User</b></p>",
+		Attachments: []Attachment{
+			{Filename: "report.csv", ContentType: "text/csv", Content: []byte("a,b\n1,2\n")},
+		},
+	}
+
+	sent, err := NewSMTPDispatcher(message, config).Dispatch()
+	if !sent || err != nil {
+		t.Fatalf("Dispatch() = %v, %v", sent, err)
+	}
+
+	received := sink.received()
+	if strings.Join(received.recipients, ",") != "user@example.com,copy@example.com,hidden@example.com" {
+		t.Errorf("recipients = %v, want to, cc and bcc", received.recipients)
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(received.data))
+	if err != nil {
+		t.Fatalf("unable to parse mail: %v", err)
+	}
+	if parsed.Header.Get("Bcc") != "" || strings.Contains(string(received.data), "hidden@example.com") {
+		t.Error("bcc recipient is in the headers")
+	}
+	if parsed.Header.Get("Cc") != "<copy@example.com>" || parsed.Header.Get("Subject") != "Your report" {
+		t.Errorf("headers = %v", parsed.Header)
+	}
+
+	mixed := readMultipart(t, parsed.Header.Get("Content-Type"), parsed.Body, "multipart/mixed")
+	if len(mixed) != 2 {
+		t.Fatalf("mixed has %d parts, want content and attachment", len(mixed))
+	}
+
+	alternative := readMultipart(t, mixed[0].header.Get("Content-Type"), bytes.NewReader(mixed[0].body), "multipart/alternative")
+	if len(alternative) != 2 {
+		t.Fatalf("alternative has %d parts, want text and html", len(alternative))
+	}
+	for i, want := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", "Hello User"},
+		{"text/html; charset=utf-8", "<p>Hello <b>User</b></p>"},
+	} {
+		body, _ := ioutil.ReadAll(quotedprintable.NewReader(bytes.NewReader(alternative[i].body)))
+		if alternative[i].header.Get("Content-Type") != want.contentType || string(body) != want.body {
+			t.Errorf("part %d = %s %q, want %s %q", i, alternative[i].header.Get("Content-Type"), body, want.contentType, want.body)
+		}
+	}
+
+	attachment := mixed[1]
+	if disposition := attachment.header.Get("Content-Disposition"); disposition != `attachment; filename=report.csv` {
+		t.Errorf("attachment disposition = %q", disposition)
+	}
+	content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(attachment.body), "\r\n", ""))
+	if err != nil || string(content) != "a,b\n1,2\n" {
+		t.Errorf("attachment content = %q, %v", content, err)
+	}
+}
+
+// readMultipart => parts of a multipart body of the given media type
+func readMultipart(t *testing.T, contentType string, body interface{ Read([]byte) (int, error) }, mediaType string) []*mimePart {
+	parsedType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || parsedType != mediaType {
+		t.Fatalf("content type = %q, %v, want %s", contentType, err, mediaType)
+	}
+
+	var parts []*mimePart
+	reader := multipart.NewReader(bufio.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextRawPart()
+		if err != nil {
+			break
+		}
+		content, _ := ioutil.ReadAll(part)
+		parts = append(parts, &mimePart{header: part.Header, body: content})
+	}
+	return parts
+}
@@ -0,0 +1,64 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// PermanentError => notification can never be delivered as it is (invalid address,
+// rejected payload, etc), retrying or failing over to another provider will not help
+type PermanentError struct {
+	Err error
+}
+
+// NewPermanentError => marks err as non retryable
+func NewPermanentError(err error) error {
+	return &PermanentError{Err: err}
+}
+
+func (err *PermanentError) Error() string {
+	if err.Err == nil {
+		return "permanent error"
+	}
+	return err.Err.Error()
+}
+
+func (err *PermanentError) Unwrap() error {
+	return err.Err
+}
+
+// IsPermanent => reports whether err (or any error it wraps) is non retryable
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+// IsPermanentStatus => reports whether a provider http status rejects the request itself
+// Auth, timeout and throttling errors are temporary (config can be fixed, limits reset).
+func IsPermanentStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	default:
+		return statusCode >= 400 && statusCode < 500
+	}
+}
+
+// RateLimitedError => notification was not sent as a rate limit was hit, it can be sent after RetryAfter
+type RateLimitedError struct {
+	Limit      string
+	RetryAfter time.Duration
+}
+
+func (err *RateLimitedError) Error() string {
+	return fmt.Sprintf("%s rate limit exceeded, retry after %v", err.Limit, err.RetryAfter)
+}
+
+// IsRateLimited => reports whether err is a rate limit error, returns the error when it is
+func IsRateLimited(err error) (*RateLimitedError, bool) {
+	var limited *RateLimitedError
+	ok := errors.As(err, &limited)
+	return limited, ok
+}
@@ -0,0 +1,51 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestPermanentError(t *testing.T) {
+	cause := errors.New("invalid address")
+	err := fmt.Errorf("sendgrid: %w", NewPermanentError(cause))
+
+	if !IsPermanent(err) {
+		t.Error("wrapped permanent error is not permanent")
+	}
+	if !errors.Is(err, cause) {
+		t.Error("permanent error does not unwrap to its cause")
+	}
+	if IsPermanent(cause) || IsPermanent(nil) {
+		t.Error("plain error is permanent")
+	}
+}
+
+func TestPermanentErrorWithoutCause(t *testing.T) {
+	err := NewPermanentError(nil)
+	if err.Error() == "" || !IsPermanent(err) {
+		t.Errorf("NewPermanentError(nil) = %q", err.Error())
+	}
+}
+
+func TestIsPermanentStatus(t *testing.T) {
+	tests := map[int]bool{
+		http.StatusBadRequest:          true,
+		http.StatusNotFound:            true,
+		http.StatusUnprocessableEntity: true,
+		http.StatusUnauthorized:        false,
+		http.StatusForbidden:           false,
+		http.StatusRequestTimeout:      false,
+		http.StatusTooManyRequests:     false,
+		http.StatusInternalServerError: false,
+		http.StatusBadGateway:          false,
+		http.StatusAccepted:            false,
+	}
+
+	for status, permanent := range tests {
+		if IsPermanentStatus(status) != permanent {
+			t.Errorf("IsPermanentStatus(%d) = %v, want %v", status, !permanent, permanent)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/trace"
+	"google.golang.org/grpc/codes"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/tracing"
+)
+
+// ErrNoProviders => no provider is configured for the notification type
+var ErrNoProviders = errors.New("no providers configured")
+
+// Candidate => a provider and the dispatcher which sends the notification through it
+type Candidate struct {
+	Provider   string
+	Dispatcher Dispatcher
+}
+
+// Result => outcome of a failover dispatch
+// Provider is the one which delivered (or permanently rejected) the notification
+type Result struct {
+	Provider string
+	Success  bool
+	Response string
+}
+
+// Limiter => rate limits requests sent to a provider
+// Returns false and how long to wait when provider can not take another request now.
+type Limiter interface {
+	AllowProvider(provider string) (bool, time.Duration)
+}
+
+// Dispatch outcomes of a provider reported to Observer
+const (
+	OutcomeSuccess     = "success"
+	OutcomeFailure     = "failure"
+	OutcomeRejected    = "rejected"
+	OutcomeCircuitOpen = "circuit_open"
+	OutcomeRateLimited = "rate_limited"
+)
+
+// Observer => told about every provider considered by a dispatch, duration is zero when
+// provider was skipped (circuit open or rate limited)
+type Observer interface {
+	ObserveDispatch(provider, outcome string, duration time.Duration)
+}
+
+// Failover => dispatches through an ordered list of providers, skipping
+// providers whose circuit breaker is open or which are rate limited
+type Failover struct {
+	mu               sync.Mutex
+	breakers         map[string]*CircuitBreaker
+	failureThreshold int
+	openTimeout      time.Duration
+	limiter          Limiter
+	observer         Observer
+}
+
+// NewFailover => returns a new failover with one breaker per provider (created lazily)
+// limiter can be nil when providers are not rate limited, observer can be nil when dispatches are not observed
+func NewFailover(failureThreshold int, openTimeout time.Duration, limiter Limiter, observer Observer) *Failover {
+	return &Failover{
+		breakers:         make(map[string]*CircuitBreaker),
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		limiter:          limiter,
+		observer:         observer,
+	}
+}
+
+// Breaker => returns circuit breaker of the given provider
+func (f *Failover) Breaker(provider string) *CircuitBreaker {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	breaker, ok := f.breakers[provider]
+	if !ok {
+		breaker = NewCircuitBreaker(provider, f.failureThreshold, f.openTimeout)
+		f.breakers[provider] = breaker
+	}
+
+	return breaker
+}
+
+// AllOpen => reports whether breakers of all the given providers are open, false when none are given
+func (f *Failover) AllOpen(providers []string) bool {
+	for _, provider := range providers {
+		if f.Breaker(provider).State() != BreakerOpen {
+			return false
+		}
+	}
+
+	return len(providers) > 0
+}
+
+// Dispatch => tries candidates in order until one succeeds
+// Returns the provider which delivered the notification. When all candidates
+// fail, the returned error has the failure reason of every provider.
+// A permanent error stops the failover, provider is healthy but rejected the notification.
+// When no provider was tried because all of them are rate limited, a RateLimitedError is returned.
+// Every tried provider gets a span, as child of the span of ctx.
+func (f *Failover) Dispatch(ctx context.Context, candidates []Candidate) (*Result, error) {
+	if len(candidates) == 0 {
+		return &Result{}, ErrNoProviders
+	}
+
+	var failures []string
+	var limited *RateLimitedError
+	tried := false
+	for _, candidate := range candidates {
+		// breaker is checked first, so a provider with an open circuit does not use up rate limit tokens
+		breaker := f.Breaker(candidate.Provider)
+		if !breaker.Allow() {
+			failures = append(failures, candidate.Provider+": circuit open")
+			f.observe(candidate.Provider, OutcomeCircuitOpen, 0)
+			continue
+		}
+
+		if f.limiter != nil {
+			if ok, wait := f.limiter.AllowProvider(candidate.Provider); !ok {
+				breaker.Release()
+				failures = append(failures, candidate.Provider+": rate limited")
+				f.observe(candidate.Provider, OutcomeRateLimited, 0)
+				if limited == nil || wait < limited.RetryAfter {
+					limited = &RateLimitedError{Limit: candidate.Provider, RetryAfter: wait}
+				}
+				continue
+			}
+		}
+
+		tried = true
+		_, span := tracing.Tracer().Start(ctx, "provider "+candidate.Provider,
+			trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(providerKey.String(candidate.Provider)))
+		start := time.Now()
+		success, err := candidate.Dispatcher.Dispatch()
+		duration := time.Since(start)
+		result := &Result{Provider: candidate.Provider, Success: success && err == nil}
+		if responder, ok := candidate.Dispatcher.(Responder); ok {
+			result.Response = responder.Response()
+		}
+
+		if result.Success {
+			breaker.Success()
+			f.observe(candidate.Provider, OutcomeSuccess, duration)
+			endSpan(span, OutcomeSuccess, nil)
+			return result, nil
+		}
+
+		if IsPermanent(err) {
+			breaker.Success()
+			f.observe(candidate.Provider, OutcomeRejected, duration)
+			endSpan(span, OutcomeRejected, err)
+			return result, err
+		}
+
+		breaker.Failure()
+		f.observe(candidate.Provider, OutcomeFailure, duration)
+		if err == nil {
+			err = errors.New("not accepted by provider")
+		}
+		endSpan(span, OutcomeFailure, err)
+		failures = append(failures, fmt.Sprintf("%s: %v", candidate.Provider, err))
+	}
+
+	if !tried && limited != nil {
+		return &Result{}, limited
+	}
+	return &Result{}, fmt.Errorf("all providers failed (%s)", strings.Join(failures, "; "))
+}
+
+func (f *Failover) observe(provider, outcome string, duration time.Duration) {
+	if f.observer != nil {
+		f.observer.ObserveDispatch(provider, outcome, duration)
+	}
+}
+
+var (
+	providerKey = kv.Key("notifications.provider")
+	outcomeKey  = kv.Key("notifications.outcome")
+)
+
+func endSpan(span trace.Span, outcome string, err error) {
+	span.SetAttributes(outcomeKey.String(outcome))
+	if err != nil {
+		span.SetStatus(codes.Unknown, err.Error())
+	}
+	span.End()
+}
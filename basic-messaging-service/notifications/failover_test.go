@@ -0,0 +1,203 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubDispatcher => returns the given result and counts dispatches
+type stubDispatcher struct {
+	sent  bool
+	err   error
+	calls int
+}
+
+func (d *stubDispatcher) Dispatch() (bool, error) {
+	d.calls++
+	return d.sent, d.err
+}
+
+func (d *stubDispatcher) Response() string {
+	return "stub response"
+}
+
+// stubLimiter => rate limits the providers in limited
+type stubLimiter map[string]time.Duration
+
+func (l stubLimiter) AllowProvider(provider string) (bool, time.Duration) {
+	wait, limited := l[provider]
+	return !limited, wait
+}
+
+// recorder => records observed dispatch outcomes as provider:outcome
+type recorder []string
+
+func (r *recorder) ObserveDispatch(provider, outcome string, _ time.Duration) {
+	*r = append(*r, provider+":"+outcome)
+}
+
+func TestFailoverUsesNextProvider(t *testing.T) {
+	observed := &recorder{}
+	f := NewFailover(5, time.Hour, nil, observed)
+	primary := &stubDispatcher{err: errors.New("timeout")}
+	secondary := &stubDispatcher{sent: true}
+
+	result, err := f.Dispatch(context.Background(), []Candidate{
+		{Provider: "sendgrid", Dispatcher: primary},
+		{Provider: "smtp", Dispatcher: secondary},
+	})
+	if err != nil || !result.Success || result.Provider != "smtp" || result.Response != "stub response" {
+		t.Fatalf("Dispatch() = %+v, %v", result, err)
+	}
+	if want := "sendgrid:failure,smtp:success"; strings.Join(*observed, ",") != want {
+		t.Errorf("observed %v, want %s", *observed, want)
+	}
+}
+
+func TestFailoverStopsOnPermanentError(t *testing.T) {
+	f := NewFailover(1, time.Hour, nil, nil)
+	primary := &stubDispatcher{err: NewPermanentError(errors.New("invalid address"))}
+	secondary := &stubDispatcher{sent: true}
+
+	result, err := f.Dispatch(context.Background(), []Candidate{
+		{Provider: "sendgrid", Dispatcher: primary},
+		{Provider: "smtp", Dispatcher: secondary},
+	})
+	if !IsPermanent(err) || result.Success || result.Provider != "sendgrid" {
+		t.Fatalf("Dispatch() = %+v, %v, want permanent error of sendgrid", result, err)
+	}
+	if secondary.calls != 0 {
+		t.Error("failed over after a permanent error")
+	}
+	// provider answered, so a rejected notification does not count against its breaker
+	if f.Breaker("sendgrid").State() != BreakerClosed {
+		t.Errorf("breaker state = %v, want closed", f.Breaker("sendgrid").State())
+	}
+}
+
+func TestFailoverSkipsOpenBreaker(t *testing.T) {
+	f := NewFailover(1, time.Hour, nil, nil)
+	failing := &stubDispatcher{}
+
+	candidates := []Candidate{
+		{Provider: "sendgrid", Dispatcher: failing},
+		{Provider: "smtp", Dispatcher: &stubDispatcher{sent: true}},
+	}
+	if _, err := f.Dispatch(context.Background(), candidates); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if !f.AllOpen([]string{"sendgrid"}) {
+		t.Fatal("breaker of not accepting provider is not open")
+	}
+
+	if _, err := f.Dispatch(context.Background(), candidates); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if failing.calls != 1 {
+		t.Errorf("provider with open breaker was called %d times, want 1", failing.calls)
+	}
+}
+
+func TestFailoverAllFailed(t *testing.T) {
+	f := NewFailover(5, time.Hour, nil, nil)
+
+	result, err := f.Dispatch(context.Background(), []Candidate{
+		{Provider: "sendgrid", Dispatcher: &stubDispatcher{err: errors.New("timeout")}},
+		{Provider: "smtp", Dispatcher: &stubDispatcher{}},
+	})
+	if err == nil || result.Success {
+		t.Fatalf("Dispatch() = %+v, %v, want error", result, err)
+	}
+	for _, reason := range []string{"sendgrid: timeout", "smtp: not accepted by provider"} {
+		if !strings.Contains(err.Error(), reason) {
+			t.Errorf("error %q does not contain %q", err, reason)
+		}
+	}
+	if IsPermanent(err) {
+		t.Error("temporary failures returned a permanent error")
+	}
+}
+
+func TestFailoverRateLimited(t *testing.T) {
+	f := NewFailover(5, time.Hour, stubLimiter{"sendgrid": 3 * time.Second, "smtp": time.Second}, nil)
+	dispatcher := &stubDispatcher{sent: true}
+
+	_, err := f.Dispatch(context.Background(), []Candidate{
+		{Provider: "sendgrid", Dispatcher: dispatcher},
+		{Provider: "smtp", Dispatcher: dispatcher},
+	})
+	limited, ok := IsRateLimited(err)
+	if !ok || limited.Limit != "smtp" || limited.RetryAfter != time.Second {
+		t.Fatalf("Dispatch() error = %v, want smtp rate limit with shortest wait", err)
+	}
+	if dispatcher.calls != 0 {
+		t.Error("rate limited provider was called")
+	}
+}
+
+func TestFailoverNoProviders(t *testing.T) {
+	if _, err := NewFailover(1, time.Hour, nil, nil).Dispatch(context.Background(), nil); err != ErrNoProviders {
+		t.Errorf("Dispatch() error = %v, want ErrNoProviders", err)
+	}
+}
+
+// countingLimiter => allows every request while counting the taken tokens, or limits all of them
+type countingLimiter struct {
+	limited bool
+	taken   map[string]int
+}
+
+func (l *countingLimiter) AllowProvider(provider string) (bool, time.Duration) {
+	if l.limited {
+		return false, time.Second
+	}
+	l.taken[provider]++
+	return true, 0
+}
+
+func TestFailoverOpenBreakerTakesNoRateLimit(t *testing.T) {
+	limiter := &countingLimiter{taken: make(map[string]int)}
+	f := NewFailover(1, time.Hour, limiter, nil)
+	candidates := []Candidate{
+		{Provider: "sendgrid", Dispatcher: &stubDispatcher{}},
+		{Provider: "smtp", Dispatcher: &stubDispatcher{sent: true}},
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := f.Dispatch(context.Background(), candidates); err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+	}
+	if limiter.taken["sendgrid"] != 1 || limiter.taken["smtp"] != 3 {
+		t.Errorf("taken tokens = %v, want 1 of sendgrid before its breaker opened and 3 of smtp", limiter.taken)
+	}
+}
+
+func TestFailoverRateLimitedProbeIsReleased(t *testing.T) {
+	limiter := &countingLimiter{taken: make(map[string]int)}
+	f := NewFailover(1, 10*time.Millisecond, limiter, nil)
+	failing := []Candidate{{Provider: "sendgrid", Dispatcher: &stubDispatcher{}}}
+	if _, err := f.Dispatch(context.Background(), failing); err == nil {
+		t.Fatal("Dispatch() of failing provider succeeded")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	limiter.limited = true
+	if _, err := f.Dispatch(context.Background(), failing); err == nil {
+		t.Fatal("Dispatch() of rate limited provider succeeded")
+	}
+
+	// rate limited probe was not sent, so the next request probes the provider
+	limiter.limited = false
+	dispatcher := &stubDispatcher{sent: true}
+	if _, err := f.Dispatch(context.Background(), []Candidate{{Provider: "sendgrid", Dispatcher: dispatcher}}); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if dispatcher.calls != 1 || f.Breaker("sendgrid").State() != BreakerClosed {
+		t.Errorf("probe calls = %d, breaker %v, want probe sent and breaker closed",
+			dispatcher.calls, f.Breaker("sendgrid").State())
+	}
+}
@@ -0,0 +1,36 @@
+package sms
+
+import (
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+)
+
+// SMS Service Providers Ordinal
+const (
+	Twilio = 0
+)
+
+// SMS Service Providers
+const (
+	TWILIO = "twilio"
+)
+
+// Dispatcher => Dispatcher Factory For all SMS dispatcher
+func Dispatcher(sender int, to, msg string, config *configs.ServerConfig) notifications.Dispatcher {
+	switch sender {
+	case Twilio:
+		return NewTwilioDispatcher(to, msg, config.Twilio)
+	default:
+		return nil
+	}
+}
+
+// GetProvider => Returns provider ordinal
+func GetProvider(sender string) int {
+	switch sender {
+	case TWILIO:
+		return Twilio
+	default:
+		return -1
+	}
+}
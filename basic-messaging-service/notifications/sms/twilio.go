@@ -0,0 +1,114 @@
+package sms
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+)
+
+// TwilioAPIEndpoint => Twilio messages endpoint, formatted with account sid
+const TwilioAPIEndpoint = "/2010-04-01/Accounts/%s/Messages.json"
+
+// twilioClient => shared http client for all twilio requests
+var twilioClient = &http.Client{Timeout: 10 * time.Second}
+
+// TwilioDispatcher , extending default dispatcher
+type TwilioDispatcher struct {
+	to         string
+	from       string
+	msg        string
+	accountSID string
+	authToken  string
+	apiURL     string
+	response   string
+}
+
+// twilioMessage => message resource returned by twilio on success
+type twilioMessage struct {
+	SID    string `json:"sid"`
+	Status string `json:"status"`
+}
+
+// twilioError => error payload returned by twilio on failed requests
+type twilioError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewTwilioDispatcher => returns a new twilio dispatcher instance
+func NewTwilioDispatcher(to, msg string, config *configs.TwilioConfig) *TwilioDispatcher {
+	return &TwilioDispatcher{
+		to:         to,
+		from:       config.FromNumber,
+		msg:        msg,
+		accountSID: config.AccountSID,
+		authToken:  config.AuthToken,
+		apiURL:     strings.TrimRight(config.APIUrl, "/"),
+	}
+}
+
+// Dispatch => Create form payload and calls twilio API with given payload (Sends SMS)
+func (td *TwilioDispatcher) Dispatch() (bool, error) {
+	form := url.Values{}
+	form.Set("To", td.to)
+	form.Set("From", td.from)
+	form.Set("Body", td.msg)
+
+	return td.SendSMS(form)
+}
+
+// Response => twilio response of last dispatch (message sid and status)
+func (td *TwilioDispatcher) Response() string {
+	return td.response
+}
+
+// SendSMS => calls twilio API (Sends SMS)
+func (td *TwilioDispatcher) SendSMS(form url.Values) (bool, error) {
+	endpoint := td.apiURL + fmt.Sprintf(TwilioAPIEndpoint, url.PathEscape(td.accountSID))
+	request, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+
+	request.SetBasicAuth(td.accountSID, td.authToken)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := twilioClient.Do(request)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	body, _ := ioutil.ReadAll(response.Body)
+	td.response = fmt.Sprintf("status %d", response.StatusCode)
+
+	// https://www.twilio.com/docs/usage/twilios-response
+	// Twilio returns 201 Created for a queued message.
+	if response.StatusCode == http.StatusCreated || response.StatusCode == http.StatusOK {
+		var message twilioMessage
+		if err := json.Unmarshal(body, &message); err == nil && message.SID != "" {
+			td.response = fmt.Sprintf("sid %s, status %s", message.SID, message.Status)
+		}
+		return true, nil
+	}
+
+	var apiErr twilioError
+	if jsonErr := json.Unmarshal(body, &apiErr); jsonErr == nil && apiErr.Message != "" {
+		err = fmt.Errorf("twilio error %d: %s", apiErr.Code, apiErr.Message)
+	} else {
+		err = fmt.Errorf("twilio returned status %d", response.StatusCode)
+	}
+
+	if notifications.IsPermanentStatus(response.StatusCode) {
+		return false, notifications.NewPermanentError(err)
+	}
+	return false, err
+}
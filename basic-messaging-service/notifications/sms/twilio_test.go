@@ -0,0 +1,118 @@
+package sms
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+)
+
+// newStub => twilio stub answering every request with status and body, requests are sent to requests
+func newStub(t *testing.T, status int, body string, requests chan<- *http.Request) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests != nil {
+			if err := r.ParseForm(); err != nil {
+				t.Errorf("unable to parse form: %v", err)
+			}
+			requests <- r
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestDispatcher(apiURL string) *TwilioDispatcher {
+	return NewTwilioDispatcher("+15550000001", "your code is 1234", &configs.TwilioConfig{
+		AccountSID: "AC123",
+		AuthToken:  "token",
+		FromNumber: "+15550000002",
+		APIUrl:     apiURL + "/",
+	})
+}
+
+func TestTwilioDispatchSendsForm(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	server := newStub(t, http.StatusCreated, `{"sid":"SM1","status":"queued"}`, requests)
+
+	td := newTestDispatcher(server.URL)
+	sent, err := td.Dispatch()
+	if !sent || err != nil {
+		t.Fatalf("Dispatch() = %v, %v, want true, nil", sent, err)
+	}
+	if td.Response() != "sid SM1, status queued" {
+		t.Errorf("Response() = %q", td.Response())
+	}
+
+	r := <-requests
+	if r.Method != http.MethodPost || r.URL.Path != fmt.Sprintf(TwilioAPIEndpoint, "AC123") {
+		t.Errorf("request = %s %s", r.Method, r.URL.Path)
+	}
+	if user, password, ok := r.BasicAuth(); !ok || user != "AC123" || password != "token" {
+		t.Errorf("basic auth = %q, %q, %v", user, password, ok)
+	}
+	want := url.Values{"To": {"+15550000001"}, "From": {"+15550000002"}, "Body": {"your code is 1234"}}
+	for key := range want {
+		if r.PostForm.Get(key) != want.Get(key) {
+			t.Errorf("form %s = %q, want %q", key, r.PostForm.Get(key), want.Get(key))
+		}
+	}
+}
+
+func TestTwilioDispatchErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		permanent bool
+		message   string
+	}{
+		{"invalid number", http.StatusBadRequest, `{"code":21211,"message":"Invalid 'To' Phone Number"}`,
+			true, "twilio error 21211: Invalid 'To' Phone Number"},
+		{"rejected without payload", http.StatusNotFound, ``, true, "twilio returned status 404"},
+		{"bad credentials", http.StatusUnauthorized, `{"code":20003,"message":"Authenticate"}`,
+			false, "twilio error 20003: Authenticate"},
+		{"throttled", http.StatusTooManyRequests, `{"code":20429,"message":"Too Many Requests"}`,
+			false, "twilio error 20429: Too Many Requests"},
+		{"server error", http.StatusInternalServerError, `<html>oops</html>`, false, "twilio returned status 500"},
+		{"unavailable", http.StatusServiceUnavailable, `{"code":20500,"message":"Internal Server Error"}`,
+			false, "twilio error 20500: Internal Server Error"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newStub(t, test.status, test.body, nil)
+
+			td := newTestDispatcher(server.URL)
+			sent, err := td.Dispatch()
+			if sent || err == nil {
+				t.Fatalf("Dispatch() = %v, %v, want false and an error", sent, err)
+			}
+			if err.Error() != test.message {
+				t.Errorf("error = %q, want %q", err.Error(), test.message)
+			}
+			if notifications.IsPermanent(err) != test.permanent {
+				t.Errorf("IsPermanent() = %v, want %v", !test.permanent, test.permanent)
+			}
+			if td.Response() != fmt.Sprintf("status %d", test.status) {
+				t.Errorf("Response() = %q", td.Response())
+			}
+		})
+	}
+}
+
+func TestTwilioDispatchUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	sent, err := newTestDispatcher(server.URL).Dispatch()
+	if sent || err == nil || notifications.IsPermanent(err) {
+		t.Fatalf("Dispatch() = %v, %v, want false and a temporary error", sent, err)
+	}
+}
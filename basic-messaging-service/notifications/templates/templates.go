@@ -0,0 +1,85 @@
+package templates
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// missingKey => referencing a variable which is not given fails the render instead of printing "<no value>"
+const missingKey = "missingkey=error"
+
+// Rendered => subject and bodies of a rendered template
+type Rendered struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Validate => parses every part of template, returns the first syntax error
+func Validate(template *protos.Template) error {
+	_, err := Render(template, nil, false)
+	return err
+}
+
+// Render => renders subject and text as text templates and html as a html template (variables are escaped)
+// When strict, every variable used by the template must be given.
+func Render(template *protos.Template, variables map[string]string, strict bool) (*Rendered, error) {
+	if variables == nil {
+		variables = map[string]string{}
+	}
+
+	subject, err := renderText("subject", template.GetSubject(), variables, strict)
+	if err != nil {
+		return nil, err
+	}
+	text, err := renderText("text", template.GetText(), variables, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	var html bytes.Buffer
+	if template.GetHtml() != "" {
+		tpl := htmltemplate.New("html")
+		if strict {
+			tpl = tpl.Option(missingKey)
+		}
+		if tpl, err = tpl.Parse(template.GetHtml()); err != nil {
+			return nil, err
+		}
+		if err := tpl.Execute(&html, variables); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Rendered{
+		// header values cannot span lines
+		Subject: strings.Join(strings.Fields(subject), " "),
+		HTML:    html.String(),
+		Text:    text,
+	}, nil
+}
+
+func renderText(name, content string, variables map[string]string, strict bool) (string, error) {
+	if content == "" {
+		return "", nil
+	}
+
+	tpl := texttemplate.New(name)
+	if strict {
+		tpl = tpl.Option(missingKey)
+	}
+	tpl, err := tpl.Parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	if err := tpl.Execute(&out, variables); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
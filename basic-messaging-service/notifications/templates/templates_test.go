@@ -0,0 +1,70 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func TestRender(t *testing.T) {
+	template := &protos.Template{
+		Subject: "Hello\n{{.name}}",
+		Html:    `<p>Hey {{.name}}, <a href="{{.link}}">verify</a></p>`,
+		Text:    "Hey {{.name}}, verify: {{.link}}",
+	}
+	variables := map[string]string{"name": "<b>Tom & Jerry</b>", "link": "https://example.com/verify?token=a&b"}
+
+	rendered, err := Render(template, variables, true)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	// header values cannot span lines
+	if want := "Hello <b>Tom & Jerry</b>"; rendered.Subject != want {
+		t.Errorf("subject = %q, want %q", rendered.Subject, want)
+	}
+	if want := `<p>Hey &lt;b&gt;Tom &amp; Jerry&lt;/b&gt;, <a href="https://example.com/verify?token=a&amp;b">verify</a></p>`; rendered.HTML != want {
+		t.Errorf("html = %q, want %q", rendered.HTML, want)
+	}
+	// plain text is not escaped
+	if want := "Hey <b>Tom & Jerry</b>, verify: https://example.com/verify?token=a&b"; rendered.Text != want {
+		t.Errorf("text = %q, want %q", rendered.Text, want)
+	}
+}
+
+func TestRenderMissingVariable(t *testing.T) {
+	tests := []struct {
+		name     string
+		template *protos.Template
+	}{
+		{"subject", &protos.Template{Subject: "Hello {{.name}}", Text: "hi"}},
+		{"text", &protos.Template{Text: "Hello {{.name}}"}},
+		{"html", &protos.Template{Html: "<p>Hello {{.name}}</p>"}},
+	}
+
+	for _, test := range tests {
+		if _, err := Render(test.template, map[string]string{"other": "value"}, true); err == nil ||
+			!strings.Contains(err.Error(), "name") {
+			t.Errorf("%s: strict Render() error = %v, want missing name", test.name, err)
+		}
+		if _, err := Render(test.template, nil, false); err != nil {
+			t.Errorf("%s: Render() error = %v, want missing variables allowed", test.name, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(&protos.Template{Text: "Hello {{.name}}", Html: "<p>{{.name}}</p>"}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	for _, template := range []*protos.Template{
+		{Text: "Hello {{.name"},
+		{Html: "<p>{{if .name}}</p>"},
+		{Subject: "{{end}}", Text: "hi"},
+	} {
+		if err := Validate(template); err == nil {
+			t.Errorf("Validate(%v) = nil, want syntax error", template)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications/email"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// attachmentTTLMargin => attachments are kept this much longer than the last retry of their message
+const attachmentTTLMargin = 24 * time.Hour
+
+// StoreAttachment => Stores attachment content and returns it with a reference instead of its content
+func (ms *MessageService) StoreAttachment(ctx context.Context, req *protos.Attachment) (*protos.Attachment, error) {
+	if len(req.GetContent()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "attachment has no content")
+	}
+	if len(req.GetContent()) > ms.config.Attachments.MaxSize {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"attachment is larger than %d bytes", ms.config.Attachments.MaxSize)
+	}
+
+	reference, err := ms.Redis.StoreBlob(ctx, req.GetContent(), ms.config.Attachments.TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Size = int64(len(req.GetContent()))
+	req.Content = nil
+	req.Reference = reference
+	return req, nil
+}
+
+// validateAttachments => checks attachments of message and their sizes, content type is guessed when not set
+func (ms *MessageService) validateAttachments(ctx context.Context, req *protos.MessageRequest) error {
+	ttl, err := ms.attachmentTTL(req)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, attachment := range req.GetEmail().GetAttachments() {
+		if err := validateAttachment(attachment); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		size := int64(len(attachment.GetContent()))
+		if attachment.GetReference() != "" {
+			size, err = ms.Redis.TouchBlob(ctx, attachment.GetReference(), ttl)
+			if err == redis.Nil {
+				return status.Errorf(codes.InvalidArgument, "attachment %s not found", attachment.GetReference())
+			} else if err != nil {
+				return err
+			}
+		}
+
+		if size > int64(ms.config.Attachments.MaxSize) {
+			return status.Errorf(codes.InvalidArgument,
+				"attachment %s is larger than %d bytes", attachment.GetFilename(), ms.config.Attachments.MaxSize)
+		}
+		attachment.Size = size
+		total += size
+	}
+
+	if total > int64(ms.config.Attachments.MaxTotalSize) {
+		return status.Errorf(codes.InvalidArgument,
+			"attachments are larger than %d bytes in total", ms.config.Attachments.MaxTotalSize)
+	}
+	return nil
+}
+
+func validateAttachment(attachment *protos.Attachment) error {
+	if attachment.GetFilename() == "" {
+		return fmt.Errorf("attachment has no filename")
+	}
+	if (len(attachment.GetContent()) == 0) == (attachment.GetReference() == "") {
+		return fmt.Errorf("attachment %s needs either content or reference", attachment.GetFilename())
+	}
+
+	if attachment.GetContentType() == "" {
+		attachment.ContentType = mime.TypeByExtension(filepath.Ext(attachment.GetFilename()))
+		if attachment.ContentType == "" {
+			attachment.ContentType = "application/octet-stream"
+		}
+	}
+	if _, _, err := mime.ParseMediaType(attachment.GetContentType()); err != nil {
+		return fmt.Errorf("attachment %s has invalid content type: %v", attachment.GetFilename(), err)
+	}
+
+	if attachment.GetInline() && attachment.GetContentId() == "" {
+		return fmt.Errorf("inline attachment %s has no content id", attachment.GetFilename())
+	}
+	if strings.ContainsAny(attachment.GetContentId(), "<> \t\r\n") {
+		return fmt.Errorf("attachment %s has invalid content id", attachment.GetFilename())
+	}
+
+	return nil
+}
+
+// offloadAttachments => stores large attachment contents out of band, so queued messages stay small
+func (ms *MessageService) offloadAttachments(ctx context.Context, req *protos.MessageRequest) error {
+	ttl, err := ms.attachmentTTL(req)
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range req.GetEmail().GetAttachments() {
+		if len(attachment.GetContent()) <= ms.config.Attachments.InlineLimit {
+			continue
+		}
+
+		reference, err := ms.Redis.StoreBlob(ctx, attachment.GetContent(), ttl)
+		if err != nil {
+			return err
+		}
+		attachment.Content = nil
+		attachment.Reference = reference
+	}
+
+	return nil
+}
+
+// attachmentTTL => how long attachments of message are stored, configured ttl unless the message
+// is sent later, then they are kept until its last retry (plus a margin)
+func (ms *MessageService) attachmentTTL(req *protos.MessageRequest) (time.Duration, error) {
+	sendAt, err := ms.sendTime(req)
+	if err != nil {
+		return 0, err
+	}
+
+	ttl := retryWindow(ms.config.Retry, ms.config.Queue.LeaseTimeout) + attachmentTTLMargin
+	if !sendAt.IsZero() {
+		ttl += time.Until(sendAt)
+	}
+	if ttl < ms.config.Attachments.TTL {
+		ttl = ms.config.Attachments.TTL
+	}
+	return ttl, nil
+}
+
+// loadAttachments => returns attachments of message with their contents
+// Attachment which is no longer stored (expired) fails the message permanently.
+func (ms *MessageService) loadAttachments(ctx context.Context, req *protos.MessageRequest) ([]email.Attachment, error) {
+	var attachments []email.Attachment
+	for _, attachment := range req.GetEmail().GetAttachments() {
+		content := attachment.GetContent()
+		if attachment.GetReference() != "" {
+			var err error
+			content, err = ms.Redis.GetBlob(ctx, attachment.GetReference())
+			if err == redis.Nil {
+				return nil, notifications.NewPermanentError(
+					fmt.Errorf("attachment %s (%s) expired", attachment.GetFilename(), attachment.GetReference()))
+			} else if err != nil {
+				return nil, err
+			}
+		}
+
+		attachments = append(attachments, email.Attachment{
+			Filename:    attachment.GetFilename(),
+			ContentType: attachment.GetContentType(),
+			Content:     content,
+			Inline:      attachment.GetInline(),
+			ContentID:   attachment.GetContentId(),
+		})
+	}
+
+	return attachments, nil
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func TestAttachmentTTL(t *testing.T) {
+	ms := &MessageService{config: &configs.ServerConfig{
+		Attachments: &configs.AttachmentConfig{TTL: 7 * 24 * time.Hour},
+		Queue:       &configs.QueueConfig{LeaseTimeout: time.Minute},
+		Retry:       &configs.RetryConfig{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: 24 * time.Hour},
+	}}
+	// 3 leases and backoffs of 1h and 2h before the last attempt
+	window := 3*time.Minute + 3*time.Hour + attachmentTTLMargin
+
+	sendIn := func(d time.Duration) *protos.MessageRequest {
+		sendAt, _ := ptypes.TimestampProto(time.Now().Add(d))
+		return &protos.MessageRequest{SendAt: sendAt}
+	}
+
+	tests := []struct {
+		name string
+		req  *protos.MessageRequest
+		min  time.Duration
+		max  time.Duration
+	}{
+		{"sent now", &protos.MessageRequest{}, 7 * 24 * time.Hour, 7 * 24 * time.Hour},
+		{"sent within ttl", sendIn(24 * time.Hour), 7 * 24 * time.Hour, 7 * 24 * time.Hour},
+		{"sent after ttl", sendIn(30 * 24 * time.Hour), 30*24*time.Hour + window - time.Minute, 30*24*time.Hour + window},
+	}
+
+	for _, test := range tests {
+		ttl, err := ms.attachmentTTL(test.req)
+		if err != nil || ttl < test.min || ttl > test.max {
+			t.Errorf("%s: attachmentTTL() = %v, %v, want between %v and %v", test.name, ttl, err, test.min, test.max)
+		}
+	}
+}
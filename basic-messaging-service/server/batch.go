@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/auth"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/metrics"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/tracing"
+)
+
+const (
+	// maxBatchSize => most messages accepted by a single AddToQueueBatch request or EnqueueStream stream
+	maxBatchSize = 10000
+	// batchChunkSize => messages written to redis in a single pipeline
+	batchChunkSize = 500
+)
+
+// AddToQueueBatch => Adds messages to their queues, same as AddToQueue for each message
+// Messages are written to redis in pipelines, a failed message does not fail the others.
+// Messages with an idempotency key are queued one by one, to check their keys.
+func (ms *MessageService) AddToQueueBatch(
+	ctx context.Context, req *protos.AddToQueueBatchRequest) (*protos.AddToQueueBatchResponse, error) {
+	if len(req.GetMessages()) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch has more than %d messages", maxBatchSize)
+	}
+
+	b := ms.newBatch(ctx)
+	for _, message := range req.GetMessages() {
+		b.add(message)
+	}
+
+	return b.finish(), nil
+}
+
+// EnqueueStream => Adds streamed messages to their queues, results are returned once client closes the stream
+// A stream takes at most maxBatchSize messages, it is ended with INVALID_ARGUMENT on the next one
+// (the messages received before it are still processed).
+func (ms *MessageService) EnqueueStream(stream protos.Notification_EnqueueStreamServer) error {
+	b := ms.newBatch(stream.Context())
+	for {
+		message, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(b.finish())
+		} else if err != nil {
+			b.abort()
+			return err
+		}
+
+		if len(b.results) >= maxBatchSize {
+			b.finish()
+			return status.Errorf(codes.InvalidArgument,
+				"stream has more than %d messages, the first %d were processed", maxBatchSize, maxBatchSize)
+		}
+		b.add(message)
+	}
+}
+
+// batch => collects messages and queues them in chunks
+type batch struct {
+	ms        *MessageService
+	ctx       context.Context
+	caller    string
+	templates templateLookup
+
+	results []*protos.BatchResult
+	pending []*db.BatchEntry
+	indexes []int
+}
+
+func (ms *MessageService) newBatch(ctx context.Context) *batch {
+	return &batch{
+		ms:        ms,
+		ctx:       ctx,
+		caller:    auth.ClientFromContext(ctx).Name,
+		templates: cachedTemplates(ms.getTemplate),
+	}
+}
+
+// add => prepares message and adds it to the pending chunk, chunk is written once it is full
+func (b *batch) add(req *protos.MessageRequest) {
+	index := len(b.results)
+	b.results = append(b.results, &protos.BatchResult{Index: int32(index)})
+	req.Caller = b.caller
+
+	if req.GetIdempotencyKey() != "" {
+		resp, err := b.ms.withIdempotency(b.ctx, queueScope, req, b.ms.enqueue)
+		b.complete(index, resp, err)
+		return
+	}
+
+	assignID(req)
+	entry, err := b.entry(req)
+	if err != nil {
+		b.complete(index, &protos.MessageResponse{Success: false, Id: req.GetId()}, err)
+		return
+	}
+
+	b.pending = append(b.pending, entry)
+	b.indexes = append(b.indexes, index)
+	if len(b.pending) >= batchChunkSize {
+		b.flush()
+	}
+}
+
+func (b *batch) entry(req *protos.MessageRequest) (entry *db.BatchEntry, err error) {
+	if err := b.ms.prepare(b.ctx, req, b.templates); err != nil {
+		return nil, err
+	}
+	if err := b.ms.takeQuota(b.ctx, req); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			b.ms.refundQuota(b.ctx, req)
+		}
+	}()
+	if err := b.ms.offloadAttachments(b.ctx, req); err != nil {
+		return nil, err
+	}
+	req.TraceContext = tracing.Inject(b.ctx)
+
+	sendAt, err := b.ms.sendTime(req)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &protos.StatusEvent{
+		State: protos.DeliveryState_QUEUED,
+		At:    ptypes.TimestampNow(),
+	}
+	if !sendAt.IsZero() {
+		event.Detail = "scheduled for " + sendAt.String()
+	}
+
+	return &db.BatchEntry{Message: req, SendAt: sendAt, Event: event}, nil
+}
+
+// flush => writes pending chunk to redis
+func (b *batch) flush() {
+	if len(b.pending) == 0 {
+		return
+	}
+
+	errs := b.ms.queue.EnqueueBatch(b.ctx, b.pending, b.ms.config.Status.TTL)
+	for i, entry := range b.pending {
+		if errs[i] == nil {
+			metrics.MessageStates.WithLabelValues(entry.Message.GetType().String(), entry.Event.GetState().String()).Inc()
+		} else {
+			b.ms.refundQuota(b.ctx, entry.Message)
+		}
+		b.complete(b.indexes[i], &protos.MessageResponse{
+			Success: errs[i] == nil,
+			Id:      entry.Message.GetId(),
+		}, errs[i])
+	}
+
+	b.pending = b.pending[:0]
+	b.indexes = b.indexes[:0]
+}
+
+// abort => drops pending chunk when batch ends without results (eg: client cancelled the stream),
+// its messages are not queued so they do not count against the quota
+func (b *batch) abort() {
+	// context of the stream may be done already
+	for _, entry := range b.pending {
+		b.ms.refundQuota(context.Background(), entry.Message)
+	}
+	b.pending = b.pending[:0]
+	b.indexes = b.indexes[:0]
+}
+
+func (b *batch) complete(index int, resp *protos.MessageResponse, err error) {
+	result := b.results[index]
+	result.Response = resp
+	if err != nil {
+		result.Code = int32(errorCode(err))
+		result.Error = err.Error()
+	}
+}
+
+// finish => writes what is left and returns results of all messages
+func (b *batch) finish() *protos.AddToQueueBatchResponse {
+	b.flush()
+
+	resp := &protos.AddToQueueBatchResponse{Results: b.results}
+	for _, result := range b.results {
+		if result.GetResponse().GetSuccess() {
+			resp.Queued++
+		} else {
+			resp.Failed++
+		}
+	}
+
+	b.ms.log.Info("Batch of %d messages from %s, queued: %d, failed: %d",
+		len(b.results), b.caller, resp.Queued, resp.Failed)
+	return resp
+}
+
+// errorCode => grpc status code of err, redis errors are reported as unavailable
+func errorCode(err error) codes.Code {
+	if s, ok := status.FromError(err); ok {
+		return s.Code()
+	}
+
+	var downErr *db.DownError
+	if errors.As(err, &downErr) {
+		return codes.Unavailable
+	}
+	return codes.Internal
+}
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/auth"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func newBatchTestService(t *testing.T) (*MessageService, *miniredis.Miniredis) {
+	rc, server := newTestRedis(t)
+	ms := &MessageService{
+		config: &configs.ServerConfig{
+			Auth:        &configs.AuthConfig{},
+			Attachments: &configs.AttachmentConfig{TTL: time.Hour},
+			Queue:       &configs.QueueConfig{LeaseTimeout: time.Minute, MaxScheduleAhead: 90 * 24 * time.Hour},
+			Retry:       &configs.RetryConfig{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour},
+			Status:      &configs.StatusConfig{TTL: time.Hour},
+		},
+		Redis: rc,
+		queue: rc,
+		log:   newTestLogger(t),
+	}
+	return ms, server
+}
+
+func smsMessage(priority protos.Priority, sendIn time.Duration) *protos.MessageRequest {
+	message := &protos.MessageRequest{Type: protos.NotificationType_SMS, To: "+15550100", Msg: "hello", Priority: priority}
+	if sendIn != 0 {
+		message.SendAt, _ = ptypes.TimestampProto(time.Now().Add(sendIn))
+	}
+	return message
+}
+
+// checkQueued => compares ready and scheduled counts of normal and high priority queues
+func checkQueued(t *testing.T, ms *MessageService, normal, high, scheduled int64) {
+	t.Helper()
+	stats, _, err := ms.Redis.QueueStats(context.Background(), protos.Priority_NORMAL, protos.Priority_HIGH)
+	if err != nil {
+		t.Fatalf("unable to read queue stats: %v", err)
+	}
+	if stats[0].Ready != normal || stats[1].Ready != high || stats[1].Scheduled != scheduled {
+		t.Errorf("queued %+v, want %d normal, %d high and %d scheduled high messages", stats, normal, high, scheduled)
+	}
+}
+
+func TestAddToQueueBatchPartialFailure(t *testing.T) {
+	ms, _ := newBatchTestService(t)
+
+	resp, err := ms.AddToQueueBatch(context.Background(), &protos.AddToQueueBatchRequest{Messages: []*protos.MessageRequest{
+		smsMessage(protos.Priority_NORMAL, 0),
+		smsMessage(protos.Priority_NORMAL, -time.Hour),
+		smsMessage(protos.Priority_HIGH, time.Hour),
+		{Type: protos.NotificationType_SMS, To: "+15550100", Category: "not a category"},
+		smsMessage(protos.Priority_HIGH, 0),
+	}})
+	if err != nil {
+		t.Fatalf("AddToQueueBatch() error = %v", err)
+	}
+	if resp.GetQueued() != 3 || resp.GetFailed() != 2 {
+		t.Errorf("queued %d, failed %d, want 3 queued and 2 failed", resp.GetQueued(), resp.GetFailed())
+	}
+
+	// results are in order of the messages
+	want := []codes.Code{codes.OK, codes.InvalidArgument, codes.OK, codes.InvalidArgument, codes.OK}
+	for i, result := range resp.GetResults() {
+		if result.GetIndex() != int32(i) || codes.Code(result.GetCode()) != want[i] ||
+			result.GetResponse().GetSuccess() != (want[i] == codes.OK) || result.GetResponse().GetId() == "" {
+			t.Errorf("result %d = %v, want index %d with code %s", i, result, i, want[i])
+		}
+	}
+	checkQueued(t, ms, 1, 1, 1)
+
+	id := resp.GetResults()[2].GetResponse().GetId()
+	if messageStatus, err := ms.Redis.GetStatus(context.Background(), id, auth.AnonymousClient); err != nil ||
+		messageStatus.GetState() != protos.DeliveryState_QUEUED {
+		t.Errorf("status of scheduled message = %v, %v, want queued", messageStatus, err)
+	}
+}
+
+func TestAddToQueueBatchTooLarge(t *testing.T) {
+	ms, _ := newBatchTestService(t)
+	messages := make([]*protos.MessageRequest, maxBatchSize+1)
+	for i := range messages {
+		messages[i] = smsMessage(protos.Priority_NORMAL, 0)
+	}
+
+	_, err := ms.AddToQueueBatch(context.Background(), &protos.AddToQueueBatchRequest{Messages: messages})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("AddToQueueBatch() error = %v, want InvalidArgument", err)
+	}
+	checkQueued(t, ms, 0, 0, 0)
+}
+
+// enqueueStream => client side of an EnqueueStream stream sending messages, then closing
+// the stream or failing with err
+type enqueueStream struct {
+	grpc.ServerStream
+	messages []*protos.MessageRequest
+	err      error
+	resp     *protos.AddToQueueBatchResponse
+}
+
+func (s *enqueueStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *enqueueStream) Recv() (*protos.MessageRequest, error) {
+	if len(s.messages) == 0 && s.err != nil {
+		return nil, s.err
+	} else if len(s.messages) == 0 {
+		return nil, io.EOF
+	}
+	message := s.messages[0]
+	s.messages = s.messages[1:]
+	return message, nil
+}
+
+func (s *enqueueStream) SendAndClose(resp *protos.AddToQueueBatchResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func TestEnqueueStream(t *testing.T) {
+	ms, _ := newBatchTestService(t)
+	stream := &enqueueStream{messages: []*protos.MessageRequest{
+		smsMessage(protos.Priority_HIGH, 0),
+		smsMessage(protos.Priority_NORMAL, -time.Hour),
+		smsMessage(protos.Priority_NORMAL, 0),
+	}}
+
+	if err := ms.EnqueueStream(stream); err != nil {
+		t.Fatalf("EnqueueStream() error = %v", err)
+	}
+	if stream.resp.GetQueued() != 2 || stream.resp.GetFailed() != 1 {
+		t.Errorf("queued %d, failed %d, want 2 queued and 1 failed", stream.resp.GetQueued(), stream.resp.GetFailed())
+	}
+	for i, result := range stream.resp.GetResults() {
+		if result.GetIndex() != int32(i) || result.GetResponse().GetSuccess() != (i != 1) {
+			t.Errorf("result %d = %v, want only message 1 failed", i, result)
+		}
+	}
+	checkQueued(t, ms, 1, 1, 0)
+}
+
+func TestEnqueueStreamTooLarge(t *testing.T) {
+	ms, _ := newBatchTestService(t)
+	stream := &enqueueStream{}
+	for i := 0; i < maxBatchSize+2; i++ {
+		stream.messages = append(stream.messages, smsMessage(protos.Priority_NORMAL, 0))
+	}
+
+	if err := ms.EnqueueStream(stream); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("EnqueueStream() error = %v, want InvalidArgument", err)
+	}
+	if stream.resp != nil || len(stream.messages) != 1 {
+		t.Errorf("stream got response %t with %d messages left, want stream ended on message over the limit",
+			stream.resp != nil, len(stream.messages))
+	}
+	// messages received before the limit are queued
+	checkQueued(t, ms, maxBatchSize, 0, 0)
+}
+
+// failingQueue => queue which fails to add any batch
+type failingQueue struct {
+	db.Queue
+}
+
+func (q failingQueue) EnqueueBatch(_ context.Context, entries []*db.BatchEntry, _ time.Duration) []error {
+	errs := make([]error, len(entries))
+	for i := range errs {
+		errs[i] = &db.DownError{}
+	}
+	return errs
+}
+
+func TestBatchRefundsQuotaOfFailedMessages(t *testing.T) {
+	quotaUsed := func(server *miniredis.Miniredis) string {
+		used, _ := server.Get(db.QuotaKey(auth.AnonymousClient, time.Now()))
+		return used
+	}
+	newService := func(t *testing.T) (*MessageService, *miniredis.Miniredis) {
+		ms, server := newBatchTestService(t)
+		ms.config.Auth.DefaultDailyQuota = 10
+		return ms, server
+	}
+
+	// send_at is checked after quota is taken
+	ms, server := newService(t)
+	_, err := ms.AddToQueueBatch(context.Background(), &protos.AddToQueueBatchRequest{Messages: []*protos.MessageRequest{
+		smsMessage(protos.Priority_NORMAL, 0),
+		smsMessage(protos.Priority_NORMAL, -time.Hour),
+		smsMessage(protos.Priority_NORMAL, 180*24*time.Hour),
+	}})
+	if err != nil {
+		t.Fatalf("AddToQueueBatch() error = %v", err)
+	}
+	if used := quotaUsed(server); used != "1" {
+		t.Errorf("quota used = %s, want 1 for the queued message only", used)
+	}
+
+	ms, server = newService(t)
+	ms.queue = failingQueue{Queue: ms.queue}
+	resp, err := ms.AddToQueueBatch(context.Background(), &protos.AddToQueueBatchRequest{Messages: []*protos.MessageRequest{
+		smsMessage(protos.Priority_NORMAL, 0),
+		smsMessage(protos.Priority_HIGH, 0),
+	}})
+	if err != nil || resp.GetFailed() != 2 {
+		t.Fatalf("AddToQueueBatch() = %v, %v, want both messages failed", resp, err)
+	}
+	if used := quotaUsed(server); used != "0" {
+		t.Errorf("quota used = %s, want 0 when queueing failed", used)
+	}
+
+	ms, server = newService(t)
+	stream := &enqueueStream{
+		messages: []*protos.MessageRequest{smsMessage(protos.Priority_NORMAL, 0), smsMessage(protos.Priority_NORMAL, 0)},
+		err:      status.Error(codes.Canceled, "context canceled"),
+	}
+	if err := ms.EnqueueStream(stream); status.Code(err) != codes.Canceled {
+		t.Fatalf("EnqueueStream() error = %v, want Canceled", err)
+	}
+	if used := quotaUsed(server); used != "0" {
+		t.Errorf("quota used = %s, want 0 for messages of a cancelled stream", used)
+	}
+	checkQueued(t, ms, 0, 0, 0)
+}
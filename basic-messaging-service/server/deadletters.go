@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+const (
+	defaultDeadLettersLimit = 50
+	maxDeadLettersLimit     = 500
+)
+
+// ListDeadLetters => Lists dead lettered messages, newest first
+func (ms *MessageService) ListDeadLetters(
+	ctx context.Context, req *protos.ListDeadLettersRequest) (*protos.ListDeadLettersResponse, error) {
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = defaultDeadLettersLimit
+	} else if limit > maxDeadLettersLimit {
+		limit = maxDeadLettersLimit
+	}
+
+	offset := req.GetOffset()
+	if offset < 0 {
+		offset = 0
+	}
+
+	deadLetters, total, err := ms.queue.ListDeadLetters(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protos.ListDeadLettersResponse{
+		DeadLetters: deadLetters,
+		Total:       total,
+	}, nil
+}
+
+// GetDeadLetter => Returns a dead lettered message with its failure
+func (ms *MessageService) GetDeadLetter(ctx context.Context, req *protos.DeadLetterRequest) (*protos.DeadLetter, error) {
+	deadLetter, err := ms.queue.GetDeadLetter(ctx, req.GetId())
+	if err == redis.Nil {
+		return nil, status.Errorf(codes.NotFound, "dead letter %s not found", req.GetId())
+	}
+
+	return deadLetter, err
+}
+
+// ReplayDeadLetter => Adds dead lettered message to its queue again with attempts reset
+func (ms *MessageService) ReplayDeadLetter(
+	ctx context.Context, req *protos.DeadLetterRequest) (*protos.MessageResponse, error) {
+	deadLetter, err := ms.GetDeadLetter(ctx, req)
+	if err != nil {
+		return &protos.MessageResponse{Success: false}, err
+	}
+
+	message := deadLetter.GetMessage()
+	message.Attempts = 0
+	message.LastError = ""
+	message.SendAt = nil
+
+	replayed, err := ms.queue.ReplayDeadLetter(ctx, req.GetId(), db.QueueKey(message.GetPriority()), message)
+	if err == nil && !replayed {
+		err = status.Errorf(codes.NotFound, "dead letter %s not found", req.GetId())
+	}
+	if replayed {
+		ms.recordStatus(ctx, message, protos.DeliveryState_QUEUED, "", "", "replayed from dead letters")
+	}
+
+	return &protos.MessageResponse{
+		Success: replayed,
+		Id:      message.GetId(),
+	}, err
+}
+
+// PurgeDeadLetters => Removes given dead letters, all of them when no ids are given
+func (ms *MessageService) PurgeDeadLetters(
+	ctx context.Context, req *protos.PurgeDeadLettersRequest) (*protos.PurgeDeadLettersResponse, error) {
+	purged, err := ms.queue.PurgeDeadLetters(ctx, req.GetIds()...)
+	if err != nil {
+		return nil, err
+	}
+
+	ms.log.Warn("Purged %d dead letters", purged)
+	return &protos.PurgeDeadLettersResponse{
+		Purged: purged,
+	}, nil
+}
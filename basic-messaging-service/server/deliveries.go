@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// WatchDeliveries => Streams delivery events (queued, dispatching, sent, failed, dead lettered) matching the filters
+// Stream is open until the client cancels it or the service stops (UNAVAILABLE),
+// events which happened before it was opened are not sent.
+func (ms *MessageService) WatchDeliveries(
+	req *protos.WatchDeliveriesRequest, stream protos.Notification_WatchDeliveriesServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	events, err := ms.Redis.SubscribeDeliveries(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "unable to watch deliveries: %v", err)
+	}
+
+	for {
+		select {
+		case <-ms.stopped:
+			return status.Error(codes.Unavailable, "service is shutting down, watch deliveries again")
+		case event, ok := <-events:
+			if !ok {
+				return stream.Context().Err()
+			}
+			if !matchesDelivery(req, event) {
+				continue
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// matchesDelivery => true when event matches all the filters of request
+func matchesDelivery(req *protos.WatchDeliveriesRequest, event *protos.DeliveryEvent) bool {
+	if req.GetTo() != "" && !strings.EqualFold(req.GetTo(), event.GetTo()) {
+		return false
+	}
+
+	if len(req.GetTypes()) > 0 {
+		matched := false
+		for _, notificationType := range req.GetTypes() {
+			matched = matched || notificationType == event.GetType()
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(req.GetStates()) > 0 {
+		matched := false
+		for _, state := range req.GetStates() {
+			matched = matched || state == event.GetEvent().GetState()
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
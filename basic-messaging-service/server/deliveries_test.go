@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// watchStream => server side of a WatchDeliveries stream, sent events are passed to sent
+type watchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *protos.DeliveryEvent
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchStream) Send(event *protos.DeliveryEvent) error {
+	s.sent <- event
+	return nil
+}
+
+func TestWatchDeliveriesEndsOnStop(t *testing.T) {
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("unable to start redis: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	ms := &MessageService{
+		Redis:   db.NewRedisClient(&configs.ServerConfig{Redis: &configs.RedisConfig{Addr: server.Addr()}}),
+		stopped: make(chan struct{}),
+	}
+	stream := &watchStream{ctx: context.Background(), sent: make(chan *protos.DeliveryEvent, 1)}
+	done := make(chan error, 1)
+	go func() {
+		done <- ms.WatchDeliveries(&protos.WatchDeliveriesRequest{To: "user@example.com"}, stream)
+	}()
+
+	for server.PubSubNumSub(db.DeliveriesChannel)[db.DeliveriesChannel] == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	server.Publish(db.DeliveriesChannel, proto.MarshalTextString(&protos.DeliveryEvent{Id: "other", To: "other@example.com"}))
+	server.Publish(db.DeliveriesChannel, proto.MarshalTextString(&protos.DeliveryEvent{Id: "1", To: "user@example.com"}))
+	select {
+	case event := <-stream.sent:
+		if event.GetId() != "1" {
+			t.Errorf("sent event %s, want only events of user@example.com", event.GetId())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("event was not sent")
+	}
+
+	ms.Stop()
+	select {
+	case err := <-done:
+		if status.Code(err) != codes.Unavailable {
+			t.Errorf("WatchDeliveries() error = %v, want UNAVAILABLE", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream is still open after Stop")
+	}
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications/email"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// reservedHeaders => set from the message itself (or by providers), cannot be given as custom headers
+var reservedHeaders = map[string]bool{
+	"from": true, "to": true, "cc": true, "bcc": true, "reply-to": true, "subject": true,
+	"date": true, "message-id": true, "mime-version": true, "content-type": true,
+	"content-transfer-encoding": true, "sender": true, "return-path": true,
+	"list-unsubscribe": true, "list-unsubscribe-post": true,
+}
+
+// List-Unsubscribe headers of one click unsubscribes (RFC 8058)
+const (
+	listUnsubscribeHeader     = "List-Unsubscribe"
+	listUnsubscribePostHeader = "List-Unsubscribe-Post"
+)
+
+// emailMessage => builds email of message from its payload, with verified sender and unique recipients
+// Same checks apply when request is received and when it is dispatched (config may change in between).
+func (ms *MessageService) emailMessage(req *protos.MessageRequest) (*email.Message, error) {
+	payload := req.GetEmail()
+
+	sender, ok := ms.config.Email.Sender(payload.GetFrom())
+	if !ok {
+		return nil, fmt.Errorf("sender %s is not a verified sender", payload.GetFrom())
+	}
+
+	message := &email.Message{
+		ID:      req.GetId(),
+		From:    email.Address{Name: sender.Name, Email: sender.Email},
+		Headers: payload.GetHeaders(),
+		Subject: req.GetSubject(),
+		HTML:    req.GetMsg(),
+		Text:    payload.GetText(),
+	}
+
+	seen := map[string]bool{}
+	to := payload.GetTo()
+	if req.GetTo() != "" {
+		to = append([]*protos.EmailAddress{{Email: req.GetTo()}}, to...)
+	}
+
+	var err error
+	if message.To, err = emailAddresses(to, seen); err != nil {
+		return nil, err
+	}
+	if message.Cc, err = emailAddresses(payload.GetCc(), seen); err != nil {
+		return nil, err
+	}
+	if message.Bcc, err = emailAddresses(payload.GetBcc(), seen); err != nil {
+		return nil, err
+	}
+	if len(message.To) == 0 {
+		return nil, errors.New("email has no recipients")
+	}
+
+	if payload.GetReplyTo() != nil {
+		replyTo, err := emailAddress(payload.GetReplyTo())
+		if err != nil {
+			return nil, err
+		}
+		message.ReplyTo = &replyTo
+	}
+
+	for key, value := range message.Headers {
+		if reservedHeaders[strings.ToLower(key)] {
+			return nil, fmt.Errorf("header %s cannot be set", key)
+		}
+		if strings.ContainsAny(key, " :\r\n") || strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("invalid header %q", key)
+		}
+	}
+
+	if link := ms.unsubscribeURL(req); link != "" {
+		headers := map[string]string{
+			listUnsubscribeHeader:     "<" + link + ">",
+			listUnsubscribePostHeader: "List-Unsubscribe=One-Click",
+		}
+		for key, value := range message.Headers {
+			headers[key] = value
+		}
+		message.Headers = headers
+	}
+
+	return message, nil
+}
+
+// dropSuppressed => removes suppressed recipients (for category) from message,
+// fails permanently when no to recipient is left
+func (ms *MessageService) dropSuppressed(ctx context.Context, message *email.Message, category string) error {
+	suppressed, err := ms.Redis.SuppressedAddresses(ctx, category, message.Recipients()...)
+	if err != nil || len(suppressed) == 0 {
+		return err
+	}
+
+	keep := func(addresses []email.Address) []email.Address {
+		var kept []email.Address
+		for _, address := range addresses {
+			if suppressed[strings.ToLower(address.Email)] {
+				ms.log.Info("Skipping suppressed recipient %s of message %s", address.Email, message.ID)
+				continue
+			}
+			kept = append(kept, address)
+		}
+		return kept
+	}
+
+	message.To = keep(message.To)
+	message.Cc = keep(message.Cc)
+	message.Bcc = keep(message.Bcc)
+	if len(message.To) == 0 {
+		return notifications.NewPermanentError(errors.New("recipients are suppressed"))
+	}
+
+	return nil
+}
+
+// emailAddresses => validates addresses, skipping the ones which are already seen
+func emailAddresses(addresses []*protos.EmailAddress, seen map[string]bool) ([]email.Address, error) {
+	var list []email.Address
+	for _, address := range addresses {
+		parsed, err := emailAddress(address)
+		if err != nil {
+			return nil, err
+		}
+
+		key := strings.ToLower(parsed.Email)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		list = append(list, parsed)
+	}
+	return list, nil
+}
+
+func emailAddress(address *protos.EmailAddress) (email.Address, error) {
+	parsed, err := mail.ParseAddress(address.GetEmail())
+	if err != nil {
+		return email.Address{}, fmt.Errorf("invalid email address %q: %v", address.GetEmail(), err)
+	}
+
+	name := address.GetName()
+	if name == "" {
+		name = parsed.Name
+	}
+	return email.Address{Name: name, Email: parsed.Address}, nil
+}
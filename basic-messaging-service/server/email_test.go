@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications/email"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func newEmailTestService() *MessageService {
+	return &MessageService{config: &configs.ServerConfig{
+		Email: &configs.EmailConfig{
+			Default: configs.Sender{Name: "Notifications", Email: "notifications@example.com"},
+			Senders: []configs.Sender{{Name: "Billing", Email: "billing@example.com"}},
+		},
+		Attachments: &configs.AttachmentConfig{MaxSize: 1 << 20, MaxTotalSize: 1 << 20, InlineLimit: 1 << 20, TTL: time.Hour},
+		Unsubscribe: &configs.UnsubscribeConfig{},
+		Queue:       &configs.QueueConfig{LeaseTimeout: time.Minute},
+		Retry:       &configs.RetryConfig{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour},
+	}}
+}
+
+func emailRequest(to string, payload *protos.EmailPayload) *protos.MessageRequest {
+	return &protos.MessageRequest{Type: protos.NotificationType_EMAIL, To: to, Subject: "hi", Msg: "<p>hi</p>", Email: payload}
+}
+
+func addresses(emails ...string) []*protos.EmailAddress {
+	var list []*protos.EmailAddress
+	for _, address := range emails {
+		list = append(list, &protos.EmailAddress{Email: address})
+	}
+	return list
+}
+
+func TestEmailMessage(t *testing.T) {
+	ms := newEmailTestService()
+	a := email.Address{Email: "a@example.com"}
+	b := email.Address{Email: "b@example.com"}
+	c := email.Address{Email: "c@example.com"}
+
+	tests := []struct {
+		name    string
+		req     *protos.MessageRequest
+		from    email.Address
+		to      []email.Address
+		cc      []email.Address
+		bcc     []email.Address
+		replyTo *email.Address
+	}{
+		{
+			name: "default sender",
+			req:  emailRequest("a@example.com", nil),
+			from: email.Address{Name: "Notifications", Email: "notifications@example.com"},
+			to:   []email.Address{a},
+		},
+		{
+			name: "verified sender",
+			req:  emailRequest("a@example.com", &protos.EmailPayload{From: "BILLING@example.com"}),
+			from: email.Address{Name: "Billing", Email: "billing@example.com"},
+			to:   []email.Address{a},
+		},
+		{
+			name: "to of message first",
+			req:  emailRequest("a@example.com", &protos.EmailPayload{To: addresses("b@example.com", "A@example.com")}),
+			to:   []email.Address{a, b},
+		},
+		{
+			name: "cc and bcc without recipients already added",
+			req: emailRequest("a@example.com", &protos.EmailPayload{
+				Cc:  addresses("b@example.com", "a@example.com"),
+				Bcc: addresses("c@example.com", "B@example.com", "c@example.com"),
+			}),
+			to:  []email.Address{a},
+			cc:  []email.Address{b},
+			bcc: []email.Address{c},
+		},
+		{
+			name:    "reply-to with name of address",
+			req:     emailRequest("a@example.com", &protos.EmailPayload{ReplyTo: &protos.EmailAddress{Email: "Support <support@example.com>"}}),
+			to:      []email.Address{a},
+			replyTo: &email.Address{Name: "Support", Email: "support@example.com"},
+		},
+		{
+			name: "reply-to with given name",
+			req: emailRequest("a@example.com", &protos.EmailPayload{
+				ReplyTo: &protos.EmailAddress{Email: "Support <support@example.com>", Name: "Help desk"},
+			}),
+			to:      []email.Address{a},
+			replyTo: &email.Address{Name: "Help desk", Email: "support@example.com"},
+		},
+	}
+
+	for _, test := range tests {
+		message, err := ms.emailMessage(test.req)
+		if err != nil {
+			t.Errorf("%s: emailMessage() error = %v", test.name, err)
+			continue
+		}
+		if test.from.Email != "" && message.From != test.from {
+			t.Errorf("%s: from = %v, want %v", test.name, message.From, test.from)
+		}
+		if !reflect.DeepEqual(message.To, test.to) || !reflect.DeepEqual(message.Cc, test.cc) ||
+			!reflect.DeepEqual(message.Bcc, test.bcc) {
+			t.Errorf("%s: to %v cc %v bcc %v, want to %v cc %v bcc %v",
+				test.name, message.To, message.Cc, message.Bcc, test.to, test.cc, test.bcc)
+		}
+		if !reflect.DeepEqual(message.ReplyTo, test.replyTo) {
+			t.Errorf("%s: reply-to = %v, want %v", test.name, message.ReplyTo, test.replyTo)
+		}
+	}
+}
+
+func TestPrepareEmail(t *testing.T) {
+	ms := newEmailTestService()
+
+	tests := []struct {
+		name string
+		req  *protos.MessageRequest
+		to   string
+		code codes.Code
+	}{
+		{"to of message", emailRequest("a@example.com", &protos.EmailPayload{To: addresses("b@example.com")}), "a@example.com", codes.OK},
+		{"to defaults to first recipient", emailRequest("", &protos.EmailPayload{To: addresses("b@example.com", "c@example.com")}), "b@example.com", codes.OK},
+		{"no recipients", emailRequest("", &protos.EmailPayload{Cc: addresses("b@example.com")}), "", codes.InvalidArgument},
+		{"invalid to", emailRequest("not an address", nil), "", codes.InvalidArgument},
+		{"invalid recipient", emailRequest("", &protos.EmailPayload{To: addresses("b@example.com", "b@")}), "", codes.InvalidArgument},
+		{"invalid cc", emailRequest("a@example.com", &protos.EmailPayload{Cc: addresses("@example.com")}), "", codes.InvalidArgument},
+		{"invalid bcc", emailRequest("a@example.com", &protos.EmailPayload{Bcc: addresses("bcc")}), "", codes.InvalidArgument},
+		{"invalid reply-to", emailRequest("a@example.com", &protos.EmailPayload{ReplyTo: &protos.EmailAddress{Email: "reply"}}), "", codes.InvalidArgument},
+		{"unverified sender", emailRequest("a@example.com", &protos.EmailPayload{From: "other@example.com"}), "", codes.InvalidArgument},
+		{"reserved header", emailRequest("a@example.com", &protos.EmailPayload{Headers: map[string]string{"Subject": "x"}}), "", codes.InvalidArgument},
+		{"header with new line", emailRequest("a@example.com", &protos.EmailPayload{Headers: map[string]string{"X-Ref": "a\r\nBcc: b@example.com"}}), "", codes.InvalidArgument},
+	}
+
+	for _, test := range tests {
+		err := ms.prepare(context.Background(), test.req, nil)
+		if code := status.Code(err); code != test.code {
+			t.Errorf("%s: prepare() error = %v, want %s", test.name, err, test.code)
+			continue
+		}
+		if err == nil && test.req.GetTo() != test.to {
+			t.Errorf("%s: to = %q, want %q", test.name, test.req.GetTo(), test.to)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Services reported by the health server
+// Overall ("") and NotificationService are ready when redis is reachable and at least one email
+// provider is not cut off by its circuit breaker. LivenessService is serving as long as the
+// worker pool keeps picking up work, it does not depend on redis or providers.
+const (
+	NotificationService = "Notification"
+	LivenessService     = "liveness"
+)
+
+// heartbeat => last time the worker pool made progress, in unix nanoseconds
+type heartbeat struct {
+	at int64
+}
+
+func (h *heartbeat) beat() {
+	atomic.StoreInt64(&h.at, time.Now().UnixNano())
+}
+
+// since => time since the last beat, zero when there was no beat yet
+func (h *heartbeat) since() time.Duration {
+	at := atomic.LoadInt64(&h.at)
+	if at == 0 {
+		return 0
+	}
+	return time.Since(time.Unix(0, at))
+}
+
+// StartHealthChecks => updates statuses of health server every interval until ctx is done
+func (ms *MessageService) StartHealthChecks(ctx context.Context, hs *health.Server) {
+	statuses := map[string]healthpb.HealthCheckResponse_ServingStatus{}
+	ticker := time.NewTicker(ms.config.Health.Interval)
+	defer ticker.Stop()
+
+	for {
+		ready := ms.checkReadiness(ctx)
+		live := ms.checkLiveness()
+		for service, status := range map[string]healthpb.HealthCheckResponse_ServingStatus{
+			"":                  ready,
+			NotificationService: ready,
+			LivenessService:     live,
+		} {
+			if previous, ok := statuses[service]; !ok || previous != status {
+				if ok {
+					ms.log.Warn("Health of service %q changed from %v to %v", service, previous, status)
+				}
+				statuses[service] = status
+				hs.SetServingStatus(service, status)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkReadiness => not serving when redis ping fails or breakers of all email providers are open
+func (ms *MessageService) checkReadiness(ctx context.Context) healthpb.HealthCheckResponse_ServingStatus {
+	pingCtx, cancel := context.WithTimeout(ctx, ms.config.Health.Timeout)
+	defer cancel()
+
+	if err := ms.Redis.Ping(pingCtx); err != nil {
+		ms.log.Error("Health check unable to reach redis: %v", err)
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	if ms.failover.AllOpen(ms.config.Providers.Email) {
+		ms.log.Error("Health check found circuit breakers of all email providers open")
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	return healthpb.HealthCheckResponse_SERVING
+}
+
+// checkLiveness => not serving when no worker picked up work for the stall timeout
+func (ms *MessageService) checkLiveness() healthpb.HealthCheckResponse_ServingStatus {
+	if stalled := ms.workers.since(); stalled > ms.config.Health.StallTimeout {
+		ms.log.Error("Health check found worker pool stalled for %v", stalled)
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	return healthpb.HealthCheckResponse_SERVING
+}
@@ -0,0 +1,22 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// HTTPHandler => routes of the public http server, used for provider webhooks and unsubscribe links
+func (ms *MessageService) HTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhooks/sendgrid", ms.SendGridWebhook)
+	mux.HandleFunc("/unsubscribe", ms.Unsubscribe)
+	return mux
+}
+
+// MetricsHandler => routes of the metrics server, kept off the public http server
+func MetricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return mux
+}
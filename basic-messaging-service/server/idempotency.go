@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/auth"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// Idempotency key scopes, same key can be used for a direct send and a queued message
+const (
+	sendScope  = "send"
+	queueScope = "queue"
+)
+
+// withIdempotency => runs handle once for the idempotency key of req, keys are per authenticated caller
+// A retried request gets the original response. Failed requests release the key, so
+// they can be retried, as nothing was sent (or queued) for them.
+func (ms *MessageService) withIdempotency(ctx context.Context, scope string, req *protos.MessageRequest,
+	handle func(context.Context, *protos.MessageRequest) (*protos.MessageResponse, error)) (*protos.MessageResponse, error) {
+	if req.GetIdempotencyKey() == "" {
+		return handle(ctx, req)
+	}
+
+	assignID(req)
+	key := db.IdempotencyKey(auth.ClientFromContext(ctx).Name, scope, req.GetIdempotencyKey())
+	pending := &protos.MessageResponse{Id: req.GetId()}
+
+	record, err := ms.Redis.ClaimIdempotencyKey(ctx, key, pending, ms.config.Idempotency.PendingTTL)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "unable to check idempotency key: %v", err)
+	}
+	if record != nil {
+		if record.Pending {
+			return record.Response, status.Errorf(codes.Aborted,
+				"request with idempotency key %s is in progress, id: %s", req.GetIdempotencyKey(), record.Response.GetId())
+		}
+
+		ms.log.Info("Duplicate request with idempotency key %s, returning response of %s",
+			req.GetIdempotencyKey(), record.Response.GetId())
+		return record.Response, nil
+	}
+
+	resp, err := handle(ctx, req)
+	if err != nil || !resp.GetSuccess() {
+		if releaseErr := ms.Redis.ReleaseIdempotencyKey(ctx, key); releaseErr != nil {
+			ms.log.Error("Error occurred while releasing idempotency key %s: %v", key, releaseErr)
+		}
+		return resp, err
+	}
+
+	if err := ms.Redis.CompleteIdempotencyKey(ctx, key, resp, ms.config.Idempotency.TTL); err != nil {
+		ms.log.Error("Error occurred while storing response of idempotency key %s: %v", key, err)
+	}
+
+	return resp, nil
+}
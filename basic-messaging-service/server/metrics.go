@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/metrics"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// StartMetricsSampler => samples queue lengths and provider breaker states every interval until ctx is done
+func (ms *MessageService) StartMetricsSampler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	priorities := []protos.Priority{protos.Priority_HIGH, protos.Priority_NORMAL, protos.Priority_BULK}
+	for {
+		stats, deadLetters, err := ms.queue.QueueStats(ctx, priorities...)
+		if err != nil {
+			ms.log.Error("Error occurred while sampling queue lengths: %v", err)
+		} else {
+			for _, queue := range stats {
+				metrics.QueueMessages.WithLabelValues(queue.Queue, "ready").Set(float64(queue.Ready))
+				metrics.QueueMessages.WithLabelValues(queue.Queue, "processing").Set(float64(queue.Processing))
+				metrics.QueueMessages.WithLabelValues(queue.Queue, "scheduled").Set(float64(queue.Scheduled))
+			}
+			metrics.DeadLetters.Set(float64(deadLetters))
+		}
+
+		for _, providers := range [][]string{ms.config.Providers.Email, ms.config.Providers.SMS} {
+			for _, provider := range providers {
+				metrics.BreakerState.WithLabelValues(provider).Set(float64(ms.failover.Breaker(provider).State()))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
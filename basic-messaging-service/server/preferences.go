@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+const maxRecipientLength = 256
+
+// GetPreferences => Returns preferences of recipient, recipients without preferences get every category
+func (ms *MessageService) GetPreferences(ctx context.Context, req *protos.PreferencesRequest) (*protos.Preferences, error) {
+	recipient, err := validateRecipient(req.GetRecipient())
+	if err != nil {
+		return nil, err
+	}
+
+	return ms.Redis.GetPreferences(ctx, recipient)
+}
+
+// UpdatePreferences => Replaces preferences of recipient, categories not given are received on every channel
+func (ms *MessageService) UpdatePreferences(ctx context.Context, req *protos.Preferences) (*protos.Preferences, error) {
+	recipient, err := validateRecipient(req.GetRecipient())
+	if err != nil {
+		return nil, err
+	}
+	req.Recipient = recipient
+
+	categories := make(map[string]*protos.ChannelPreferences, len(req.GetCategories()))
+	for category, preferences := range req.GetCategories() {
+		if err := normalizeCategory(&category); err != nil {
+			return nil, err
+		} else if category == "" {
+			return nil, status.Error(codes.InvalidArgument, "preferences of empty category")
+		}
+		if preferences == nil {
+			preferences = &protos.ChannelPreferences{}
+		}
+		for _, channel := range preferences.GetChannels() {
+			if _, ok := protos.NotificationType_name[int32(channel)]; !ok {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid channel %d of category %s", channel, category)
+			}
+		}
+		categories[category] = preferences
+	}
+	req.Categories = categories
+	req.UpdatedAt = ptypes.TimestampNow()
+
+	if err := ms.Redis.SetPreferences(ctx, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func validateRecipient(recipient string) (string, error) {
+	recipient = strings.TrimSpace(recipient)
+	if recipient == "" {
+		return "", status.Error(codes.InvalidArgument, "recipient is required")
+	} else if len(recipient) > maxRecipientLength {
+		return "", status.Errorf(codes.InvalidArgument, "recipient is longer than %d characters", maxRecipientLength)
+	}
+	return recipient, nil
+}
+
+// checkPreferences => fails permanently when recipient opted out of category of message on its channel
+// Messages without category or of a transactional category are always allowed. Only preferences of
+// the first recipient are checked for emails with many recipients.
+func (ms *MessageService) checkPreferences(ctx context.Context, req *protos.MessageRequest) error {
+	if ms.config.Unsubscribe.IsTransactional(req.GetCategory()) {
+		return nil
+	}
+
+	recipient := req.GetRecipient()
+	if recipient == "" {
+		recipient = req.GetTo()
+	}
+	preferences, err := ms.Redis.GetPreferences(ctx, recipient)
+	if err != nil {
+		return err
+	}
+
+	category, ok := preferences.GetCategories()[req.GetCategory()]
+	if !ok {
+		return nil
+	}
+	for _, channel := range category.GetChannels() {
+		if channel == req.GetType() {
+			return nil
+		}
+	}
+
+	return notifications.NewPermanentError(
+		&optedOutError{recipient: recipient, category: req.GetCategory(), channel: req.GetType()})
+}
+
+// optedOutError => recipient opted out of category of message on its channel
+type optedOutError struct {
+	recipient string
+	category  string
+	channel   protos.NotificationType
+}
+
+func (err *optedOutError) Error() string {
+	return fmt.Sprintf("recipient %s opted out of %s messages on %v", err.recipient, err.category, err.channel)
+}
+
+// isOptedOut => reports whether err (or any error it wraps) is an opt-out of the recipient
+func isOptedOut(err error) bool {
+	var optedOut *optedOutError
+	return errors.As(err, &optedOut)
+}
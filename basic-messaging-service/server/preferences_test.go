@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func newPreferencesTestService(t *testing.T) *MessageService {
+	rc, _ := newTestRedis(t)
+	config := &configs.ServerConfig{
+		Auth:        &configs.AuthConfig{},
+		Status:      &configs.StatusConfig{TTL: time.Hour},
+		RateLimit:   &configs.RateLimitConfig{},
+		Unsubscribe: &configs.UnsubscribeConfig{TransactionalCategories: []string{"security"}},
+	}
+	return &MessageService{
+		config:  config,
+		Redis:   rc,
+		limiter: newRateLimiter(rc, config.RateLimit, nil),
+		log:     newTestLogger(t),
+	}
+}
+
+func channels(types ...protos.NotificationType) *protos.ChannelPreferences {
+	return &protos.ChannelPreferences{Channels: types}
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	ms := newPreferencesTestService(t)
+	ctx := context.Background()
+
+	updated, err := ms.UpdatePreferences(ctx, &protos.Preferences{
+		Recipient: " User@Example.com ",
+		Categories: map[string]*protos.ChannelPreferences{
+			"Marketing": channels(protos.NotificationType_SMS),
+			"digest":    nil,
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePreferences() error = %v", err)
+	}
+	if updated.GetRecipient() != "User@Example.com" || updated.GetUpdatedAt() == nil {
+		t.Errorf("UpdatePreferences() = %v, want trimmed recipient and update time", updated)
+	}
+
+	// recipients are looked up case insensitively
+	preferences, err := ms.GetPreferences(ctx, &protos.PreferencesRequest{Recipient: "user@example.com"})
+	if err != nil {
+		t.Fatalf("GetPreferences() error = %v", err)
+	}
+	categories := preferences.GetCategories()
+	if len(categories) != 2 || len(categories["marketing"].GetChannels()) != 1 ||
+		categories["marketing"].GetChannels()[0] != protos.NotificationType_SMS || len(categories["digest"].GetChannels()) != 0 {
+		t.Errorf("categories = %v, want marketing on sms and no digest", categories)
+	}
+
+	preferences, err = ms.GetPreferences(ctx, &protos.PreferencesRequest{Recipient: "other@example.com"})
+	if err != nil || len(preferences.GetCategories()) != 0 {
+		t.Errorf("GetPreferences() of other recipient = %v, %v, want no preferences", preferences, err)
+	}
+}
+
+func TestUpdatePreferencesInvalid(t *testing.T) {
+	ms := newPreferencesTestService(t)
+
+	tests := []struct {
+		name        string
+		preferences *protos.Preferences
+	}{
+		{"no recipient", &protos.Preferences{Recipient: " "}},
+		{"empty category", &protos.Preferences{Recipient: "user@example.com",
+			Categories: map[string]*protos.ChannelPreferences{" ": channels()}}},
+		{"invalid category", &protos.Preferences{Recipient: "user@example.com",
+			Categories: map[string]*protos.ChannelPreferences{"news letter": channels()}}},
+		{"invalid channel", &protos.Preferences{Recipient: "user@example.com",
+			Categories: map[string]*protos.ChannelPreferences{"news": channels(protos.NotificationType(42))}}},
+	}
+	for _, test := range tests {
+		if _, err := ms.UpdatePreferences(context.Background(), test.preferences); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: UpdatePreferences() error = %v, want InvalidArgument", test.name, err)
+		}
+	}
+}
+
+func TestCheckPreferences(t *testing.T) {
+	ms := newPreferencesTestService(t)
+	ctx := context.Background()
+	if _, err := ms.UpdatePreferences(ctx, &protos.Preferences{
+		Recipient: "user@example.com",
+		Categories: map[string]*protos.ChannelPreferences{
+			"marketing": channels(protos.NotificationType_SMS),
+			"security":  channels(),
+		},
+	}); err != nil {
+		t.Fatalf("UpdatePreferences() error = %v", err)
+	}
+
+	message := func(category, recipient string, channel protos.NotificationType) *protos.MessageRequest {
+		return &protos.MessageRequest{Type: channel, To: "user@example.com", Recipient: recipient, Category: category}
+	}
+	tests := []struct {
+		name     string
+		req      *protos.MessageRequest
+		optedOut bool
+	}{
+		{"without category", message("", "", protos.NotificationType_EMAIL), false},
+		{"category without preferences", message("digest", "", protos.NotificationType_EMAIL), false},
+		{"opted out channel", message("marketing", "", protos.NotificationType_EMAIL), true},
+		{"allowed channel", message("marketing", "", protos.NotificationType_SMS), false},
+		{"transactional category", message("security", "", protos.NotificationType_EMAIL), false},
+		{"preferences of recipient", message("marketing", "other-user", protos.NotificationType_EMAIL), false},
+	}
+	for _, test := range tests {
+		err := ms.checkPreferences(ctx, test.req)
+		if optedOut := isOptedOut(err); optedOut != test.optedOut || (err != nil) != test.optedOut {
+			t.Errorf("%s: checkPreferences() error = %v, want opted out %t", test.name, err, test.optedOut)
+		}
+		// queued messages of opted out recipients are not retried
+		if test.optedOut && !notifications.IsPermanent(err) {
+			t.Errorf("%s: checkPreferences() error = %v, want permanent", test.name, err)
+		}
+	}
+}
+
+func TestSendNotificationOptedOut(t *testing.T) {
+	ms := newPreferencesTestService(t)
+	ctx := context.Background()
+	if _, err := ms.UpdatePreferences(ctx, &protos.Preferences{
+		Recipient:  "+15550100",
+		Categories: map[string]*protos.ChannelPreferences{"marketing": channels()},
+	}); err != nil {
+		t.Fatalf("UpdatePreferences() error = %v", err)
+	}
+
+	resp, err := ms.SendNotification(ctx, &protos.MessageRequest{
+		Type: protos.NotificationType_SMS, To: "+15550100", Msg: "sale", Category: "marketing",
+	})
+	if status.Code(err) != codes.FailedPrecondition || resp.GetSuccess() {
+		t.Errorf("SendNotification() = %v, %v, want FailedPrecondition", resp, err)
+	}
+}
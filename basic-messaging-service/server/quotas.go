@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// takeQuota => counts message against the daily quota of its caller
+// Returns RESOURCE_EXHAUSTED when caller used up its quota. Quotas fail open like rate limits,
+// a redis error never blocks a notification.
+func (ms *MessageService) takeQuota(ctx context.Context, req *protos.MessageRequest) error {
+	limit := ms.config.Auth.DailyQuota(req.GetCaller())
+	if limit <= 0 {
+		return nil
+	}
+
+	allowed, count, err := ms.Redis.TakeQuota(ctx, req.GetCaller(), 1, int64(limit))
+	if err != nil {
+		ms.log.Error("Error occurred while checking quota of %s, allowing message: %v", req.GetCaller(), err)
+		return nil
+	}
+	if !allowed {
+		return status.Errorf(codes.ResourceExhausted,
+			"daily quota of %s is used up, %d of %d messages sent today", req.GetCaller(), count, limit)
+	}
+
+	return nil
+}
+
+// refundQuota => uncounts message which was counted by takeQuota but was not sent
+func (ms *MessageService) refundQuota(ctx context.Context, req *protos.MessageRequest) {
+	if ms.config.Auth.DailyQuota(req.GetCaller()) <= 0 {
+		return
+	}
+
+	if err := ms.Redis.RefundQuota(ctx, req.GetCaller(), 1); err != nil {
+		ms.log.Error("Error occurred while refunding quota of %s: %v", req.GetCaller(), err)
+	}
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/auth"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/logging"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// rateLimiter => redis backed token buckets per recipient, caller and provider
+// Limits fail open, a redis error never blocks a notification.
+type rateLimiter struct {
+	redis  *db.Redis
+	config *configs.RateLimitConfig
+	log    *logging.LogWrapper
+}
+
+func newRateLimiter(redis *db.Redis, config *configs.RateLimitConfig, l *logging.LogWrapper) *rateLimiter {
+	return &rateLimiter{redis: redis, config: config, log: l}
+}
+
+// Allow => checks recipient and caller limits of message
+// Returns a RateLimitedError when message has to wait.
+func (rl *rateLimiter) Allow(ctx context.Context, message *protos.MessageRequest) error {
+	var buckets []db.Bucket
+	if rl.config.Recipient != nil && message.GetTo() != "" {
+		buckets = append(buckets, db.Bucket{Key: db.RecipientBucketKey(message.GetTo()), Rule: rl.config.Recipient})
+	}
+
+	// rules are configured with lower-cased names
+	caller := strings.ToLower(message.GetCaller())
+	if caller == "" {
+		caller = auth.AnonymousClient
+	}
+	rule, ok := rl.config.Callers[caller]
+	if !ok {
+		rule = rl.config.DefaultCaller
+	}
+	if rule != nil {
+		buckets = append(buckets, db.Bucket{Key: db.CallerBucketKey(caller), Rule: rule})
+	}
+
+	allowed, wait, err := rl.redis.TakeTokens(ctx, buckets...)
+	if err != nil {
+		rl.log.Error("Error occurred while checking rate limits, allowing message: %v", err)
+		return nil
+	}
+	if !allowed {
+		return &notifications.RateLimitedError{Limit: "recipient/caller", RetryAfter: wait}
+	}
+
+	return nil
+}
+
+// AllowProvider => checks provider limit, implements notifications.Limiter
+func (rl *rateLimiter) AllowProvider(provider string) (bool, time.Duration) {
+	rule, ok := rl.config.Providers[provider]
+	if !ok {
+		return true, 0
+	}
+
+	allowed, wait, err := rl.redis.TakeTokens(context.Background(), db.Bucket{Key: db.ProviderBucketKey(provider), Rule: rule})
+	if err != nil {
+		rl.log.Error("Error occurred while checking %s rate limit, allowing message: %v", provider, err)
+		return true, 0
+	}
+
+	return allowed, wait
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func TestRateLimiterCallerIsCaseInsensitive(t *testing.T) {
+	rc, server := newTestRedis(t)
+	rl := newRateLimiter(rc, &configs.RateLimitConfig{
+		Callers: map[string]*configs.RateRule{"billing": {Limit: 1, Period: time.Hour, Burst: 1}},
+	}, nil)
+	ctx := context.Background()
+
+	for i, caller := range []string{"Billing", "BILLING", "billing"} {
+		err := rl.Allow(ctx, &protos.MessageRequest{To: "user@example.com", Caller: caller})
+		if _, limited := notifications.IsRateLimited(err); limited != (i > 0) {
+			t.Errorf("Allow() of %s error = %v, want rate limited after the first message", caller, err)
+		}
+	}
+	if !server.Exists(db.CallerBucketKey("billing")) || server.Exists(db.CallerBucketKey("Billing")) {
+		t.Errorf("bucket keys = %v, want only lower-cased caller", server.Keys())
+	}
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// backoff => base * 2^(attempt-1) for given attempt (1 based), capped at max delay
+func backoff(attempt int, config *configs.RetryConfig) time.Duration {
+	delay := config.MaxDelay
+	if shift := uint(attempt - 1); shift < 32 {
+		if backoff := config.BaseDelay << shift; backoff > 0 && backoff < delay {
+			delay = backoff
+		}
+	}
+	return delay
+}
+
+// retryDelay => exponential backoff for given attempt (1 based) with equal jitter,
+// delay is somewhere between half and full of its backoff
+func retryDelay(attempt int, config *configs.RetryConfig) time.Duration {
+	delay := backoff(attempt, config)
+
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+	return time.Duration(half + jitterRand.Int63n(half+1))
+}
+
+// retryWindow => longest time from first dispatch attempt of a message to its last one,
+// when every attempt holds its lease until it times out and waits its full backoff
+func retryWindow(config *configs.RetryConfig, leaseTimeout time.Duration) time.Duration {
+	window := time.Duration(config.MaxAttempts) * leaseTimeout
+	for attempt := 1; attempt < config.MaxAttempts; attempt++ {
+		window += backoff(attempt, config)
+	}
+	return window
+}
+
+// retryOrDeadLetter => failed message is retried after backoff, or dead lettered when
+// it failed with a permanent error or ran out of attempts
+func (ms *MessageService) retryOrDeadLetter(ctx context.Context, queue db.Queue, lease *db.Lease, dispatchErr error) {
+	message := lease.Message
+	message.Attempts++
+	if dispatchErr != nil {
+		message.LastError = dispatchErr.Error()
+	} else {
+		message.LastError = "not sent"
+	}
+
+	if notifications.IsPermanent(dispatchErr) || int(message.Attempts) >= ms.config.Retry.MaxAttempts {
+		deadLetter := &protos.DeadLetter{
+			Id:       message.GetId(),
+			Message:  message,
+			Error:    message.LastError,
+			Attempts: message.Attempts,
+			FailedAt: ptypes.TimestampNow(),
+		}
+
+		if err := queue.DeadLetter(ctx, lease, deadLetter); err != nil {
+			ms.log.Error("Error occurred while dead lettering message: %v", err)
+			return
+		}
+		ms.recordStatus(ctx, message, protos.DeliveryState_DEAD_LETTERED, "", "", message.LastError)
+		ms.log.Warn("Message dead lettered after %d attempts, id: %s, error: %s",
+			message.Attempts, deadLetter.Id, message.LastError)
+		return
+	}
+
+	delay := retryDelay(int(message.Attempts), ms.config.Retry)
+	if err := queue.Retry(ctx, lease, message, time.Now().Add(delay)); err != nil {
+		ms.log.Error("Error occurred while scheduling message retry: %v", err)
+		return
+	}
+	ms.recordStatus(ctx, message, protos.DeliveryState_QUEUED, "", "", fmt.Sprintf("retry in %v", delay))
+	ms.log.Info("Message failed (attempt %d), retrying in %v, error: %s", message.Attempts, delay, message.LastError)
+}
+
+// deferMessage => rate limited message goes back to its scheduled set until limit allows it,
+// it is not counted as a failed attempt
+func (ms *MessageService) deferMessage(ctx context.Context, queue db.Queue, lease *db.Lease, limitErr error) {
+	delay := time.Second
+	if limited, ok := notifications.IsRateLimited(limitErr); ok && limited.RetryAfter > delay {
+		delay = limited.RetryAfter
+	}
+
+	if err := queue.Retry(ctx, lease, lease.Message, time.Now().Add(delay)); err != nil {
+		ms.log.Error("Error occurred while deferring rate limited message: %v", err)
+		return
+	}
+
+	ms.recordStatus(ctx, lease.Message, protos.DeliveryState_QUEUED, "", "", fmt.Sprintf("%v, deferred", limitErr))
+	ms.log.Info("Message %s deferred by %v: %v", lease.Message.GetId(), delay, limitErr)
+}
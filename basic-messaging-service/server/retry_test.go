@@ -0,0 +1,42 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+)
+
+func TestRetryDelay(t *testing.T) {
+	config := &configs.RetryConfig{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: time.Minute}
+
+	tests := []struct {
+		attempt int
+		backoff time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{6, 32 * time.Second},
+		{7, time.Minute},
+		{40, time.Minute},
+		{100, time.Minute},
+	}
+
+	for _, test := range tests {
+		for i := 0; i < 50; i++ {
+			delay := retryDelay(test.attempt, config)
+			if delay < test.backoff/2 || delay > test.backoff {
+				t.Fatalf("retryDelay(%d) = %v, want between %v and %v", test.attempt, delay, test.backoff/2, test.backoff)
+			}
+		}
+	}
+}
+
+func TestRetryDelayTinyBase(t *testing.T) {
+	config := &configs.RetryConfig{BaseDelay: time.Nanosecond, MaxDelay: time.Nanosecond}
+
+	if delay := retryDelay(1, config); delay != time.Nanosecond {
+		t.Errorf("retryDelay() = %v, want %v", delay, time.Nanosecond)
+	}
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
+)
+
+// Queue scheduling policies
+const (
+	StrictScheduling   = "strict"
+	WeightedScheduling = "weighted"
+)
+
+// Scheduler => decides in which order workers should look into priority queues
+type Scheduler interface {
+	// Next returns queue keys in the order they should be tried
+	Next() []string
+}
+
+// priorityOrder => queue keys from highest to lowest priority
+var priorityOrder = []string{db.HighPriorityQueue, db.NormalPriorityQueue, db.BulkPriorityQueue}
+
+// NewScheduler => returns scheduler for configured policy, weighted by default
+func NewScheduler(config *configs.QueueConfig) Scheduler {
+	if config.Scheduling == StrictScheduling {
+		return &strictScheduler{}
+	}
+
+	return newWeightedScheduler([]int{config.HighWeight, config.NormalWeight, config.BulkWeight})
+}
+
+// strictScheduler => lower priority queues are only looked into when higher ones are empty
+type strictScheduler struct{}
+
+func (s *strictScheduler) Next() []string {
+	return priorityOrder
+}
+
+// weightedScheduler => smooth weighted round robin over the priority queues
+// Picked queue is tried first, followed by the rest in priority order,
+// so a worker never idles while any queue has messages.
+type weightedScheduler struct {
+	mu      sync.Mutex
+	weights []int
+	current []int
+	total   int
+}
+
+func newWeightedScheduler(weights []int) *weightedScheduler {
+	total := 0
+	for i, weight := range weights {
+		if weight < 1 {
+			weights[i] = 1
+		}
+		total += weights[i]
+	}
+
+	return &weightedScheduler{
+		weights: weights,
+		current: make([]int, len(weights)),
+		total:   total,
+	}
+}
+
+func (s *weightedScheduler) Next() []string {
+	s.mu.Lock()
+	picked := 0
+	for i := range s.current {
+		s.current[i] += s.weights[i]
+		if s.current[i] > s.current[picked] {
+			picked = i
+		}
+	}
+	s.current[picked] -= s.total
+	s.mu.Unlock()
+
+	order := make([]string, 0, len(priorityOrder))
+	order = append(order, priorityOrder[picked])
+	for i, key := range priorityOrder {
+		if i != picked {
+			order = append(order, key)
+		}
+	}
+
+	return order
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func TestSendTime(t *testing.T) {
+	ms := &MessageService{config: &configs.ServerConfig{
+		Queue: &configs.QueueConfig{MaxScheduleAhead: 24 * time.Hour},
+	}}
+	sendIn := func(d time.Duration) *timestamp.Timestamp {
+		sendAt, _ := ptypes.TimestampProto(time.Now().Add(d))
+		return sendAt
+	}
+
+	tests := []struct {
+		name      string
+		sendAt    *timestamp.Timestamp
+		scheduled bool
+		code      codes.Code
+	}{
+		{"no send_at", nil, false, codes.OK},
+		{"within tolerance of now", sendIn(-30 * time.Second), false, codes.OK},
+		{"in future", sendIn(time.Hour), true, codes.OK},
+		{"in past", sendIn(-time.Hour), false, codes.InvalidArgument},
+		{"beyond max schedule ahead", sendIn(25 * time.Hour), false, codes.InvalidArgument},
+		{"invalid", &timestamp.Timestamp{Nanos: -1}, false, codes.InvalidArgument},
+	}
+
+	for _, test := range tests {
+		sendAt, err := ms.sendTime(&protos.MessageRequest{SendAt: test.sendAt})
+		if code := status.Code(err); code != test.code {
+			t.Errorf("%s: got code %s, want %s (%v)", test.name, code, test.code, err)
+		}
+		if scheduled := !sendAt.IsZero(); scheduled != test.scheduled {
+			t.Errorf("%s: got send time %s, want scheduled %t", test.name, sendAt, test.scheduled)
+		}
+	}
+
+	ms.config.Queue.MaxScheduleAhead = 0
+	if _, err := ms.sendTime(&protos.MessageRequest{SendAt: sendIn(365 * 24 * time.Hour)}); err != nil {
+		t.Errorf("without max schedule ahead got %v, want any future send_at", err)
+	}
+}
+
+func TestPromoterPromotesDueMessageOnce(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	log := newTestLogger(t)
+	queue, err := db.NewQueue(&configs.QueueConfig{Backend: db.ListsBackend}, rc)
+	if err != nil {
+		t.Fatalf("unable to create queue: %v", err)
+	}
+	ctx := context.Background()
+
+	due := &protos.MessageRequest{Id: "due", To: "user@example.com", Priority: protos.Priority_HIGH}
+	later := &protos.MessageRequest{Id: "later", To: "user@example.com", Priority: protos.Priority_NORMAL}
+	if err := queue.Schedule(ctx, due, time.Now()); err != nil {
+		t.Fatalf("unable to schedule message: %v", err)
+	}
+	if err := queue.Schedule(ctx, later, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("unable to schedule message: %v", err)
+	}
+
+	// two instances promote the same scheduled sets
+	promoting, stop := context.WithCancel(ctx)
+	var promoters sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		ms := &MessageService{queue: queue, log: log}
+		promoters.Add(1)
+		go func() {
+			defer promoters.Done()
+			ms.StartPromoter(promoting, time.Millisecond)
+		}()
+	}
+	t.Cleanup(func() {
+		stop()
+		promoters.Wait()
+	})
+
+	stats := func() []db.QueueStats {
+		stats, _, err := rc.QueueStats(ctx, protos.Priority_HIGH, protos.Priority_NORMAL, protos.Priority_BULK)
+		if err != nil {
+			t.Fatalf("unable to read queue stats: %v", err)
+		}
+		return stats
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for stats()[0].Ready == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("due message was not promoted")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// give promoters a few more rounds to promote it again
+	time.Sleep(50 * time.Millisecond)
+	stop()
+	promoters.Wait()
+
+	want := []db.QueueStats{
+		{Queue: db.HighPriorityQueue, Ready: 1},
+		{Queue: db.NormalPriorityQueue, Scheduled: 1},
+		{Queue: db.BulkPriorityQueue},
+	}
+	for i, got := range stats() {
+		if got != want[i] {
+			t.Errorf("got stats %+v, want %+v", got, want[i])
+		}
+	}
+
+	message, err := queue.PopAny(ctx, db.HighPriorityQueue)
+	if err != nil || message.GetId() != "due" {
+		t.Errorf("got message %v (%v) in high priority queue, want due", message, err)
+	}
+}
@@ -2,125 +2,367 @@ package server
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
 	"github.com/frost060/go-microservice-basic/basic-messaging-service/logging"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
+	"go.opentelemetry.io/otel/api/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/auth"
 	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/metrics"
 	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
 	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications/email"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications/sms"
 	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/tracing"
 )
 
 // MessageService => Sends Notificaiotns
 type MessageService struct {
-	config *configs.ServerConfig
-	Redis  *db.Redis
-	log    *logging.LogWrapper
+	config    *configs.ServerConfig
+	Redis     *db.Redis
+	queue     db.Queue
+	log       *logging.LogWrapper
+	failover  *notifications.Failover
+	scheduler Scheduler
+	limiter   *rateLimiter
+	workers   *heartbeat
+
+	sendGridKey *ecdsa.PublicKey
+
+	// stopped => closed by Stop, ends open streams so a graceful stop of the grpc server does not wait for them
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMessageService => returns a new message service, messages are queued in queue
+func NewMessageService(
+	config *configs.ServerConfig, redis *db.Redis, queue db.Queue, l *logging.LogWrapper) *MessageService {
+	limiter := newRateLimiter(redis, config.RateLimit, l)
+	failover := notifications.NewFailover(
+		config.Breaker.FailureThreshold, config.Breaker.OpenTimeout, limiter, metrics.DispatchObserver{})
+	ms := &MessageService{
+		config:    config,
+		Redis:     redis,
+		queue:     queue,
+		log:       l,
+		failover:  failover,
+		scheduler: NewScheduler(config.Queue),
+		limiter:   limiter,
+		workers:   &heartbeat{},
+		stopped:   make(chan struct{}),
+	}
+
+	if config.SendGrid.WebhookVerificationKey == "" {
+		l.Warn("SENDGRID_WEBHOOK_VERIFICATION_KEY is not set, sendgrid events are rejected")
+	} else if key, err := email.ParseWebhookKey(config.SendGrid.WebhookVerificationKey); err != nil {
+		l.Error("Invalid sendgrid webhook verification key, sendgrid events are rejected: %v", err)
+	} else {
+		ms.sendGridKey = key
+	}
+
+	return ms
 }
 
-// NewMessageService => returns a new message service
-func NewMessageService(config *configs.ServerConfig, redis *db.Redis, l *logging.LogWrapper) *MessageService {
-	return &MessageService{config, redis, l}
+// Stop => Ends open WatchDeliveries streams, clients are told to watch again (on another instance)
+func (ms *MessageService) Stop() {
+	ms.stopOnce.Do(func() {
+		close(ms.stopped)
+	})
 }
 
 // SendNotification => Sends a notification without processing (dont add to queue)
 // Used for forgot password, verify account, login OTP, etc.
+// Subject is ignored for SMS notifications, subject and content are rendered from template_id when given.
+// Returns RESOURCE_EXHAUSTED when a recipient, caller or provider rate limit is hit, or daily quota of caller is used up,
+// FAILED_PRECONDITION when recipient opted out of category of the message.
 func (ms *MessageService) SendNotification(
 	ctx context.Context, req *protos.MessageRequest) (*protos.MessageResponse, error) {
-	messageType := req.GetType()
+	req.Caller = auth.ClientFromContext(ctx).Name
+	return ms.withIdempotency(ctx, sendScope, req, ms.sendNow)
+}
 
-	var dispatcher notifications.Dispatcher
-	msg := req.GetMsg()
-	to := req.GetTo()
-	subject := req.GetSubject()
+// sendNow => dispatches message right away if it is within rate limits
+func (ms *MessageService) sendNow(ctx context.Context, req *protos.MessageRequest) (*protos.MessageResponse, error) {
+	assignID(req)
 
-	switch messageType {
-	case protos.NotificationType_EMAIL:
-		dispatcher = email.Dispatcher(
-			email.GetProvider(ms.config.Providers.Email), to, subject, msg, ms.config)
-	default:
-		dispatcher = nil
+	if err := ms.prepare(ctx, req, ms.getTemplate); err != nil {
+		return &protos.MessageResponse{
+			Success: false,
+			Id:      req.GetId(),
+		}, err
+	}
+	if err := ms.limiter.Allow(ctx, req); err != nil {
+		ms.recordStatus(ctx, req, protos.DeliveryState_FAILED, "", "", err.Error())
+		return &protos.MessageResponse{
+			Success: false,
+			Id:      req.GetId(),
+		}, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	// quota is taken once the rate limits admitted the message, and refunded when it was not sent
+	if err := ms.takeQuota(ctx, req); err != nil {
+		return &protos.MessageResponse{
+			Success: false,
+			Id:      req.GetId(),
+		}, err
 	}
 
-	if dispatcher == nil {
+	resp, err := ms.dispatch(ctx, req)
+	if !resp.GetSuccess() {
+		ms.refundQuota(ctx, req)
+	}
+	if _, limited := notifications.IsRateLimited(err); limited {
 		return &protos.MessageResponse{
 			Success: false,
-		}, errors.New("invalid message type")
+			Id:      req.GetId(),
+		}, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if isOptedOut(err) {
+		return resp, status.Error(codes.FailedPrecondition, err.Error())
 	}
 
-	success, err := dispatcher.Dispatch()
-	ms.log.Info(fmt.Sprintf("Success: %v, Error: %v", success, err))
+	return resp, err
+}
+
+// dispatch => sends message through its providers and records the delivery status
+// Used for direct sends and by the queue workers.
+func (ms *MessageService) dispatch(
+	ctx context.Context, req *protos.MessageRequest) (resp *protos.MessageResponse, err error) {
+	assignID(req)
+
+	ctx, span := tracing.Tracer().Start(ctx, "dispatch", trace.WithAttributes(messageAttributes(req)...))
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Unknown, err.Error())
+		}
+		span.End()
+	}()
+
+	candidates, err := ms.candidates(ctx, req)
+	if err == nil && len(candidates) == 0 {
+		err = notifications.NewPermanentError(errors.New("invalid message type"))
+	}
+	if err != nil {
+		ms.recordStatus(ctx, req, protos.DeliveryState_FAILED, "", "", err.Error())
+		return &protos.MessageResponse{
+			Success: false,
+			Id:      req.GetId(),
+		}, err
+	}
+
+	ms.recordStatus(ctx, req, protos.DeliveryState_DISPATCHING, "", "", "")
+	result, err := ms.failover.Dispatch(ctx, candidates)
+	ms.log.Info("Id: %s, Type: %v, Provider: %s, Success: %v, Error: %v",
+		req.GetId(), req.GetType(), result.Provider, result.Success, err)
+
+	if result.Success {
+		ms.recordStatus(ctx, req, protos.DeliveryState_SENT, result.Provider, result.Response, "")
+	} else {
+		detail := "not sent"
+		if err != nil {
+			detail = err.Error()
+		}
+		ms.recordStatus(ctx, req, protos.DeliveryState_FAILED, result.Provider, result.Response, detail)
+	}
 
 	return &protos.MessageResponse{
-		Success: success,
+		Success:  result.Success,
+		Provider: result.Provider,
+		Id:       req.GetId(),
 	}, err
 }
 
-func (ms *MessageService) AddToQueue(
-	ctx context.Context, req *protos.MessageRequest) (*protos.MessageResponse, error) {
+// prepare => renders template of message and validates it before it is sent or queued
+func (ms *MessageService) prepare(ctx context.Context, req *protos.MessageRequest, lookup templateLookup) error {
+	if err := normalizeCategory(&req.Category); err != nil {
+		return err
+	}
+	if req.GetRecipient() != "" {
+		recipient, err := validateRecipient(req.GetRecipient())
+		if err != nil {
+			return err
+		}
+		req.Recipient = recipient
+	}
+	if err := ms.renderTemplate(ctx, req, lookup); err != nil {
+		return err
+	}
 
-	ok, err := ms.Redis.Push(ctx, "default", req)
+	if req.GetType() == protos.NotificationType_EMAIL {
+		// first recipient is used for status tracking and rate limits
+		if req.GetTo() == "" && len(req.GetEmail().GetTo()) > 0 {
+			req.To = req.GetEmail().GetTo()[0].GetEmail()
+		}
+		if _, err := ms.emailMessage(req); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if err := ms.validateAttachments(ctx, req); err != nil {
+			return err
+		}
+	}
 
-	return &protos.MessageResponse{
-		Success: ok,
-	}, err
+	return nil
 }
 
-func (ms *MessageService) RemoveFromQueue(ctx context.Context, _ *empty.Empty) (*protos.MessageRequest, error) {
-	return ms.Redis.Pop(ctx, "default")
+// candidates => returns dispatchers for configured providers of message type, in failover order
+// Suppressed recipients are dropped and preferences of the recipient are enforced.
+// Returned error is permanent unless it is a redis failure.
+func (ms *MessageService) candidates(ctx context.Context, req *protos.MessageRequest) ([]notifications.Candidate, error) {
+	if err := ms.checkPreferences(ctx, req); err != nil {
+		return nil, err
+	}
+
+	var candidates []notifications.Candidate
+	switch req.GetType() {
+	case protos.NotificationType_EMAIL:
+		message, err := ms.emailMessage(req)
+		if err != nil {
+			return nil, notifications.NewPermanentError(err)
+		}
+		if err := ms.dropSuppressed(ctx, message, ms.suppressionCategory(req)); err != nil {
+			return nil, err
+		}
+		if message.Attachments, err = ms.loadAttachments(ctx, req); err != nil {
+			return nil, err
+		}
+		for _, provider := range ms.config.Providers.Email {
+			dispatcher := email.Dispatcher(email.GetProvider(provider), message, ms.config)
+			candidates = ms.appendCandidate(candidates, provider, dispatcher)
+		}
+	case protos.NotificationType_SMS:
+		suppressed, err := ms.Redis.SuppressedAddresses(ctx, ms.suppressionCategory(req), req.GetTo())
+		if err != nil {
+			return nil, err
+		} else if len(suppressed) > 0 {
+			return nil, notifications.NewPermanentError(fmt.Errorf("recipient %s is suppressed", req.GetTo()))
+		}
+		for _, provider := range ms.config.Providers.SMS {
+			dispatcher := sms.Dispatcher(sms.GetProvider(provider), req.GetTo(), req.GetMsg(), ms.config)
+			candidates = ms.appendCandidate(candidates, provider, dispatcher)
+		}
+	}
+
+	return candidates, nil
 }
 
-type Worker struct {
-	ID int
+func (ms *MessageService) appendCandidate(
+	candidates []notifications.Candidate, provider string, dispatcher notifications.Dispatcher) []notifications.Candidate {
+	if dispatcher == nil {
+		ms.log.Warn("Unknown provider %s, skipping", provider)
+		return candidates
+	}
+
+	return append(candidates, notifications.Candidate{Provider: provider, Dispatcher: dispatcher})
 }
 
-type WorkerPool struct {
-	Pool chan Worker
+// AddToQueue => Adds message to the queue of its priority, dispatched later by workers
+// Messages with send_at in future are held back until they are due (see StartPromoter)
+func (ms *MessageService) AddToQueue(
+	ctx context.Context, req *protos.MessageRequest) (*protos.MessageResponse, error) {
+	req.Caller = auth.ClientFromContext(ctx).Name
+	return ms.withIdempotency(ctx, queueScope, req, ms.enqueue)
 }
 
-func (ms *MessageService) newWorkerPool(noOfRoutines int) *WorkerPool {
-	workerPool := make(chan Worker, noOfRoutines)
-	for i := 0; i < noOfRoutines; i++ {
-		worker := &Worker{
-			ID: i,
+// enqueue => adds message to its queue or scheduled set and records it as queued
+func (ms *MessageService) enqueue(ctx context.Context, req *protos.MessageRequest) (*protos.MessageResponse, error) {
+	assignID(req)
+
+	// rendered once, queued message keeps the content of the template version it was created with
+	if err := ms.prepare(ctx, req, ms.getTemplate); err != nil {
+		return &protos.MessageResponse{
+			Success: false,
+			Id:      req.GetId(),
+		}, err
+	}
+	if err := ms.takeQuota(ctx, req); err != nil {
+		return &protos.MessageResponse{
+			Success: false,
+			Id:      req.GetId(),
+		}, err
+	}
+	queued := false
+	defer func() {
+		if !queued {
+			ms.refundQuota(ctx, req)
 		}
-		ms.log.Info("Created worker with id %d", i)
-		workerPool <- *worker
+	}()
+	if err := ms.offloadAttachments(ctx, req); err != nil {
+		return &protos.MessageResponse{
+			Success: false,
+			Id:      req.GetId(),
+		}, err
+	}
+	req.TraceContext = tracing.Inject(ctx)
+
+	sendAt, err := ms.sendTime(req)
+	if err != nil {
+		return &protos.MessageResponse{
+			Success: false,
+			Id:      req.GetId(),
+		}, err
+	}
+	if !sendAt.IsZero() {
+		err = ms.queue.Schedule(ctx, req, sendAt)
+		queued = err == nil
+		if queued {
+			ms.recordStatus(ctx, req, protos.DeliveryState_QUEUED, "", "", "scheduled for "+sendAt.String())
+		}
+		return &protos.MessageResponse{
+			Success: queued,
+			Id:      req.GetId(),
+		}, err
 	}
 
-	return &WorkerPool{
-		Pool: workerPool,
+	ok, err := ms.queue.Push(ctx, db.QueueKey(req.GetPriority()), req)
+	queued = ok
+	if ok {
+		ms.recordStatus(ctx, req, protos.DeliveryState_QUEUED, "", "", "")
 	}
+
+	return &protos.MessageResponse{
+		Success: ok,
+		Id:      req.GetId(),
+	}, err
 }
 
-// https://play.golang.org/p/HovNRgp6FxH
-func (ms *MessageService) StartDispatchRedis(noOfRoutines int, redis *db.Redis) {
-	workerPool := ms.newWorkerPool(noOfRoutines)
-	for {
-		worker := <-workerPool.Pool
-		go func() {
-			ctx := context.Background()
-			message, err := redis.Pop(ctx, "default")
-			if err != nil {
-				time.Sleep(1 * time.Minute)
-				workerPool.Pool <- worker
-				return
-			}
-
-			resp, err := ms.SendNotification(ctx, message)
-			if err != nil || !resp.Success {
-				ms.log.Error("Error occurred while dispatching message, pushing back to redis")
-				_, _ = redis.Push(ctx, "default", message)
-			} else {
-				ms.log.Info("Successfully sent message, by worker: %d", worker.ID)
-			}
-
-			workerPool.Pool <- worker
-		}()
+// sendAtTolerance => how far in past send_at may be (clock skew of callers), it is sent now
+const sendAtTolerance = time.Minute
+
+// sendTime => send_at of message when it is in future, zero time when message should be queued now
+// send_at further in past than sendAtTolerance or beyond the max schedule ahead is invalid.
+func (ms *MessageService) sendTime(req *protos.MessageRequest) (time.Time, error) {
+	if req.GetSendAt() == nil {
+		return time.Time{}, nil
+	}
+
+	sendAt, err := ptypes.Timestamp(req.GetSendAt())
+	if err != nil {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "invalid send_at: %v", err)
+	}
+	now := time.Now()
+	if sendAt.Before(now.Add(-sendAtTolerance)) {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "send_at %s is in the past", sendAt)
+	}
+	if ahead := ms.config.Queue.MaxScheduleAhead; ahead > 0 && sendAt.After(now.Add(ahead)) {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "send_at %s is more than %s ahead", sendAt, ahead)
 	}
+	if !sendAt.After(now) {
+		return time.Time{}, nil
+	}
+
+	return sendAt, nil
+}
+
+// RemoveFromQueue => Removes next message from the queues, highest priority first
+func (ms *MessageService) RemoveFromQueue(ctx context.Context, _ *empty.Empty) (*protos.MessageRequest, error) {
+	return ms.queue.PopAny(ctx, priorityOrder...)
 }
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"os"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/logging"
+)
+
+// newTestRedis => redis client of a miniredis server closed at the end of the test
+func newTestRedis(t *testing.T) (*db.Redis, *miniredis.Miniredis) {
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("unable to start redis: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	return db.NewRedisClient(&configs.ServerConfig{Redis: &configs.RedisConfig{Addr: server.Addr()}}), server
+}
+
+// newTestLogger => logger writing its file into a temporary working directory of the test
+func newTestLogger(t *testing.T) *logging.LogWrapper {
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("logs", 0755); err != nil {
+		t.Fatalf("unable to create logs directory: %v", err)
+	}
+
+	return logging.NewLogger()
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/auth"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/metrics"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// newMessageID => unique id assigned to every message
+func newMessageID() string {
+	return uuid.New().String()
+}
+
+// assignID => gives message an id, unless it already has one (eg: retried or replayed message)
+func assignID(message *protos.MessageRequest) {
+	if message.GetId() == "" {
+		message.Id = newMessageID()
+	}
+}
+
+// recordStatus => records state change of a message, failures are only logged
+// as status tracking should never fail the delivery itself
+func (ms *MessageService) recordStatus(
+	ctx context.Context, message *protos.MessageRequest, state protos.DeliveryState, provider, response, detail string) {
+	event := &protos.StatusEvent{
+		State:    state,
+		At:       ptypes.TimestampNow(),
+		Provider: provider,
+		Detail:   detail,
+	}
+
+	metrics.MessageStates.WithLabelValues(message.GetType().String(), state.String()).Inc()
+	if err := ms.Redis.RecordStatus(ctx, message, event, response, ms.config.Status.TTL); err != nil {
+		ms.log.Error("Error occurred while recording status %v of message %s: %v", state, message.GetId(), err)
+	}
+}
+
+// GetMessageStatus => Returns current delivery status of a message with its history
+// Callers only see status of their own messages.
+func (ms *MessageService) GetMessageStatus(
+	ctx context.Context, req *protos.MessageStatusRequest) (*protos.MessageStatus, error) {
+	messageStatus, err := ms.Redis.GetStatus(ctx, req.GetId(), auth.ClientFromContext(ctx).Name)
+	if err == redis.Nil {
+		return nil, status.Errorf(codes.NotFound, "status of message %s not found", req.GetId())
+	}
+
+	return messageStatus, err
+}
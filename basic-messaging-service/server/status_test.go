@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/auth"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func TestGetMessageStatus(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	ms := &MessageService{
+		config: &configs.ServerConfig{Status: &configs.StatusConfig{TTL: time.Hour}},
+		Redis:  rc,
+	}
+	billing := auth.WithClient(context.Background(), &auth.Client{Name: "billing"})
+
+	message := &protos.MessageRequest{Id: "1", Type: protos.NotificationType_EMAIL, To: "user@example.com", Caller: "billing"}
+	ms.recordStatus(billing, message, protos.DeliveryState_QUEUED, "", "", "")
+	ms.recordStatus(billing, message, protos.DeliveryState_SENT, "sendgrid", "202 Accepted", "")
+
+	messageStatus, err := ms.GetMessageStatus(billing, &protos.MessageStatusRequest{Id: "1"})
+	if err != nil {
+		t.Fatalf("GetMessageStatus() error = %v", err)
+	}
+	if messageStatus.GetState() != protos.DeliveryState_SENT || messageStatus.GetProvider() != "sendgrid" ||
+		messageStatus.GetProviderResponse() != "202 Accepted" || messageStatus.GetTo() != "user@example.com" {
+		t.Errorf("GetMessageStatus() = %v, want sent by sendgrid to user@example.com", messageStatus)
+	}
+	if len(messageStatus.GetHistory()) != 2 || messageStatus.GetHistory()[0].GetState() != protos.DeliveryState_QUEUED {
+		t.Errorf("history = %v, want queued and sent", messageStatus.GetHistory())
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		id   string
+	}{
+		{"unknown message", billing, "2"},
+		{"other caller", auth.WithClient(context.Background(), &auth.Client{Name: "marketing"}), "1"},
+		{"anonymous caller", context.Background(), "1"},
+	}
+	for _, test := range tests {
+		if _, err := ms.GetMessageStatus(test.ctx, &protos.MessageStatusRequest{Id: test.id}); status.Code(err) != codes.NotFound {
+			t.Errorf("%s: got %v, want NotFound", test.name, err)
+		}
+	}
+}
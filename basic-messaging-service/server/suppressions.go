@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+const (
+	defaultSuppressionsLimit = 50
+	maxSuppressionsLimit     = 500
+)
+
+// Suppression sources, besides the providers reporting bounces
+const (
+	apiSource         = "api"
+	unsubscribeSource = "unsubscribe"
+)
+
+var categoryRegex = regexp.MustCompile(`^[a-z0-9_.-]{1,64}$`)
+
+// AddSuppression => Suppresses an address for all messages or for messages of a category
+func (ms *MessageService) AddSuppression(ctx context.Context, req *protos.Suppression) (*protos.Suppression, error) {
+	if strings.TrimSpace(req.GetAddress()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "suppression has no address")
+	}
+	if err := normalizeCategory(&req.Category); err != nil {
+		return nil, err
+	}
+
+	req.Address = strings.TrimSpace(req.GetAddress())
+	if req.GetSource() == "" {
+		req.Source = apiSource
+	}
+	req.CreatedAt = ptypes.TimestampNow()
+
+	if err := ms.Redis.AddSuppression(ctx, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// RemoveSuppression => Removes suppression of an address (for a category), so it gets messages again
+func (ms *MessageService) RemoveSuppression(
+	ctx context.Context, req *protos.SuppressionRequest) (*protos.RemoveSuppressionResponse, error) {
+	if err := normalizeCategory(&req.Category); err != nil {
+		return nil, err
+	}
+
+	removed, err := ms.Redis.RemoveSuppression(ctx, req.GetAddress(), req.GetCategory())
+	if err != nil {
+		return nil, err
+	}
+	return &protos.RemoveSuppressionResponse{Removed: removed}, nil
+}
+
+// ListSuppressions => Lists suppressions newest first, of all categories when category is not given
+func (ms *MessageService) ListSuppressions(
+	ctx context.Context, req *protos.ListSuppressionsRequest) (*protos.ListSuppressionsResponse, error) {
+	if err := normalizeCategory(&req.Category); err != nil {
+		return nil, err
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = defaultSuppressionsLimit
+	} else if limit > maxSuppressionsLimit {
+		limit = maxSuppressionsLimit
+	}
+
+	offset := req.GetOffset()
+	if offset < 0 {
+		offset = 0
+	}
+
+	suppressions, total, err := ms.Redis.ListSuppressions(ctx, req.GetCategory(), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protos.ListSuppressionsResponse{
+		Suppressions: suppressions,
+		Total:        total,
+	}, nil
+}
+
+// normalizeCategory => lower cases category and checks it, empty category is valid
+func normalizeCategory(category *string) error {
+	*category = strings.ToLower(strings.TrimSpace(*category))
+	if *category != "" && !categoryRegex.MatchString(*category) {
+		return status.Errorf(codes.InvalidArgument,
+			"invalid category %q, use up to 64 letters, digits, '.', '_' or '-'", *category)
+	}
+	return nil
+}
+
+// suppressionCategory => category to check suppressions of message for, transactional messages
+// are only held back by suppressions of all categories
+func (ms *MessageService) suppressionCategory(req *protos.MessageRequest) string {
+	if ms.config.Unsubscribe.IsTransactional(req.GetCategory()) {
+		return ""
+	}
+	return req.GetCategory()
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications/templates"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+var templateIDRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// CreateTemplate => Stores a new template as its first version
+// Returns ALREADY_EXISTS when template exists, use UpdateTemplate to add a new version.
+func (ms *MessageService) CreateTemplate(ctx context.Context, req *protos.Template) (*protos.Template, error) {
+	if err := validateTemplate(req); err != nil {
+		return nil, err
+	}
+
+	req.CreatedAt = ptypes.TimestampNow()
+	created, err := ms.Redis.CreateTemplate(ctx, req)
+	if err != nil {
+		return nil, err
+	} else if !created {
+		return nil, status.Errorf(codes.AlreadyExists, "template %s already exists", req.GetId())
+	}
+
+	return req, nil
+}
+
+// UpdateTemplate => Stores template as a new version, previous versions are kept
+func (ms *MessageService) UpdateTemplate(ctx context.Context, req *protos.Template) (*protos.Template, error) {
+	if err := validateTemplate(req); err != nil {
+		return nil, err
+	}
+
+	req.CreatedAt = ptypes.TimestampNow()
+	err := ms.Redis.UpdateTemplate(ctx, req)
+	if err == redis.Nil {
+		return nil, status.Errorf(codes.NotFound, "template %s not found", req.GetId())
+	} else if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// GetTemplate => Returns given version of a template, latest version when version is not set
+func (ms *MessageService) GetTemplate(ctx context.Context, req *protos.GetTemplateRequest) (*protos.Template, error) {
+	template, err := ms.Redis.GetTemplate(ctx, req.GetId(), req.GetVersion())
+	if err == redis.Nil {
+		return nil, status.Errorf(codes.NotFound, "template %s (version %d) not found", req.GetId(), req.GetVersion())
+	}
+
+	return template, err
+}
+
+// ListTemplates => Lists latest version of every template
+func (ms *MessageService) ListTemplates(
+	ctx context.Context, _ *protos.ListTemplatesRequest) (*protos.ListTemplatesResponse, error) {
+	list, err := ms.Redis.ListTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protos.ListTemplatesResponse{Templates: list}, nil
+}
+
+// validateTemplate => checks template id and parses its parts, so broken templates are never stored
+func validateTemplate(template *protos.Template) error {
+	if !templateIDRegex.MatchString(template.GetId()) {
+		return status.Errorf(codes.InvalidArgument,
+			"invalid template id %q, use up to 128 letters, digits, '.', '_' or '-'", template.GetId())
+	}
+	if template.GetHtml() == "" && template.GetText() == "" {
+		return status.Error(codes.InvalidArgument, "template needs a html or text body")
+	}
+	if err := templates.Validate(template); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid template: %v", err)
+	}
+
+	return nil
+}
+
+// SeedTemplates => creates templates of json files in dir which do not exist yet, existing templates
+// are left as they are (they may have been updated since). Nothing is seeded when dir is empty.
+func (ms *MessageService) SeedTemplates(ctx context.Context, dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	created := 0
+	for _, file := range files {
+		template, err := readTemplate(file)
+		if err != nil {
+			return err
+		}
+
+		_, err = ms.CreateTemplate(ctx, template)
+		if status.Code(err) == codes.AlreadyExists {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("unable to create template of %s: %v", file, err)
+		}
+		created++
+	}
+
+	ms.log.Info("Seeded %d of %d templates in %s", created, len(files), dir)
+	return nil
+}
+
+// readTemplate => template of a json file, fields are named as in Template message
+func readTemplate(file string) (*protos.Template, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	template := &protos.Template{}
+	if err := jsonpb.Unmarshal(f, template); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %v", file, err)
+	}
+	return template, nil
+}
+
+// templateLookup => returns given version of a template, latest version when version is 0
+type templateLookup func(ctx context.Context, id string, version int32) (*protos.Template, error)
+
+// getTemplate => templateLookup reading the template from redis every time
+func (ms *MessageService) getTemplate(ctx context.Context, id string, version int32) (*protos.Template, error) {
+	return ms.GetTemplate(ctx, &protos.GetTemplateRequest{Id: id, Version: version})
+}
+
+// cachedTemplates => templateLookup remembering found templates, so a batch reads each template once
+func cachedTemplates(lookup templateLookup) templateLookup {
+	type templateRef struct {
+		id      string
+		version int32
+	}
+	cache := map[templateRef]*protos.Template{}
+
+	return func(ctx context.Context, id string, version int32) (*protos.Template, error) {
+		ref := templateRef{id: id, version: version}
+		if template, ok := cache[ref]; ok {
+			return template, nil
+		}
+
+		template, err := lookup(ctx, id, version)
+		if err == nil {
+			cache[ref] = template
+		}
+		return template, err
+	}
+}
+
+// renderTemplate => renders subject and content of message from its template, if it has one
+// Emails use html content (text as its alternative), SMS use text content.
+func (ms *MessageService) renderTemplate(ctx context.Context, req *protos.MessageRequest, lookup templateLookup) error {
+	if req.GetTemplateId() == "" {
+		return nil
+	}
+
+	template, err := lookup(ctx, req.GetTemplateId(), req.GetTemplateVersion())
+	if err != nil {
+		return err
+	}
+
+	rendered, err := templates.Render(template, req.GetVariables(), true)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "cannot render template %s: %v", template.GetId(), err)
+	}
+
+	switch req.GetType() {
+	case protos.NotificationType_EMAIL:
+		req.Msg = rendered.HTML
+		if req.Email == nil {
+			req.Email = &protos.EmailPayload{}
+		}
+		req.Email.Text = rendered.Text
+		if rendered.Subject != "" {
+			req.Subject = rendered.Subject
+		}
+	case protos.NotificationType_SMS:
+		if rendered.Text == "" {
+			return status.Errorf(codes.InvalidArgument, "template %s has no text body for sms", template.GetId())
+		}
+		req.Msg = rendered.Text
+	}
+
+	req.TemplateVersion = template.GetVersion()
+	return nil
+}
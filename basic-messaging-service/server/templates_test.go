@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+func TestSeedTemplates(t *testing.T) {
+	dir, err := filepath.Abs("../templates")
+	if err != nil {
+		t.Fatalf("unable to find templates: %v", err)
+	}
+	rc, _ := newTestRedis(t)
+	ms := &MessageService{Redis: rc, log: newTestLogger(t)}
+	ctx := context.Background()
+
+	if err := ms.SeedTemplates(ctx, dir); err != nil {
+		t.Fatalf("SeedTemplates() error = %v", err)
+	}
+	for _, id := range []string{"forgot_password", "verify_email"} {
+		template, err := ms.GetTemplate(ctx, &protos.GetTemplateRequest{Id: id})
+		if err != nil || template.GetVersion() != 1 || template.GetHtml() == "" || template.GetText() == "" {
+			t.Errorf("template %s = %v, %v, want first version with html and text", id, template, err)
+		}
+	}
+
+	// templates updated since are not replaced by the seeded version
+	if _, err := ms.UpdateTemplate(ctx, &protos.Template{Id: "verify_email", Text: "Verify: {{.link}}"}); err != nil {
+		t.Fatalf("UpdateTemplate() error = %v", err)
+	}
+	if err := ms.SeedTemplates(ctx, dir); err != nil {
+		t.Fatalf("SeedTemplates() again error = %v", err)
+	}
+	if template, err := ms.GetTemplate(ctx, &protos.GetTemplateRequest{Id: "verify_email"}); err != nil ||
+		template.GetVersion() != 2 {
+		t.Errorf("template verify_email = %v, %v, want updated version kept", template, err)
+	}
+
+	if err := ms.SeedTemplates(ctx, ""); err != nil {
+		t.Errorf("SeedTemplates() without dir error = %v", err)
+	}
+}
+
+func TestSeedTemplatesInvalid(t *testing.T) {
+	rc, _ := newTestRedis(t)
+	ms := &MessageService{Redis: rc, log: newTestLogger(t)}
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid json", `{"id": "welcome"`},
+		{"unknown field", `{"id": "welcome", "body": "hi"}`},
+		{"invalid template", `{"id": "welcome", "text": "Hello {{.name"}`},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "welcome.json"), []byte(test.content), 0644); err != nil {
+			t.Fatalf("unable to write template: %v", err)
+		}
+		if err := ms.SeedTemplates(context.Background(), dir); err == nil {
+			t.Errorf("%s: SeedTemplates() = nil, want error", test.name)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/trace"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/tracing"
+)
+
+// Span attributes of messages, recipients are left out as spans are exported outside the service
+var (
+	messageIDKey       = kv.Key("notifications.message_id")
+	messageTypeKey     = kv.Key("notifications.type")
+	messageCategoryKey = kv.Key("notifications.category")
+	messageAttemptsKey = kv.Key("notifications.attempts")
+	queueKey           = kv.Key("notifications.queue")
+)
+
+func messageAttributes(req *protos.MessageRequest) []kv.KeyValue {
+	return []kv.KeyValue{
+		messageIDKey.String(req.GetId()),
+		messageTypeKey.String(req.GetType().String()),
+		messageCategoryKey.String(req.GetCategory()),
+		messageAttemptsKey.Int32(req.GetAttempts()),
+	}
+}
+
+// startProcessSpan => starts span of a worker processing a queued message, in the trace of the request
+// which queued it. Retries of a message are all part of that trace.
+func startProcessSpan(lease *db.Lease) (context.Context, trace.Span) {
+	ctx := tracing.Extract(context.Background(), lease.Message.GetTraceContext())
+	return tracing.Tracer().Start(ctx, "process "+lease.Queue,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(append(messageAttributes(lease.Message), queueKey.String(lease.Queue))...))
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+var errInvalidUnsubscribeToken = errors.New("invalid unsubscribe link")
+
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Unsubscribe</title></head>
+<body>
+{{if .Error}}
+<p>{{.Error}}</p>
+{{else if .Done}}
+<p>{{.Address}} is unsubscribed{{if .Category}} from {{.Category}} emails{{end}}.</p>
+{{else}}
+<form method="post">
+<p>Unsubscribe {{.Address}}{{if .Category}} from {{.Category}} emails{{end}}?</p>
+<button type="submit">Unsubscribe</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+// unsubscribeToken => signed token of address and category, does not expire
+func (ms *MessageService) unsubscribeToken(address, category string) string {
+	payload := []byte(strings.ToLower(address) + "\n" + category)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(ms.unsubscribeSignature(payload))
+}
+
+func (ms *MessageService) unsubscribeSignature(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(ms.config.Unsubscribe.Secret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// parseUnsubscribeToken => returns address and category of a token signed by us
+func (ms *MessageService) parseUnsubscribeToken(token string) (string, string, error) {
+	if ms.config.Unsubscribe.Secret == "" {
+		return "", "", errInvalidUnsubscribeToken
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return "", "", errInvalidUnsubscribeToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", "", errInvalidUnsubscribeToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, ms.unsubscribeSignature(payload)) {
+		return "", "", errInvalidUnsubscribeToken
+	}
+
+	fields := strings.SplitN(string(payload), "\n", 2)
+	if len(fields) != 2 {
+		return "", "", errInvalidUnsubscribeToken
+	}
+	return fields[0], fields[1], nil
+}
+
+// unsubscribeURL => one click unsubscribe url of message recipient, empty for transactional messages
+// or when unsubscribe links are not configured. Link unsubscribes the first recipient of an email.
+func (ms *MessageService) unsubscribeURL(req *protos.MessageRequest) string {
+	config := ms.config.Unsubscribe
+	if config.URL == "" || config.Secret == "" || config.IsTransactional(req.GetCategory()) {
+		return ""
+	}
+
+	link, err := url.Parse(config.URL)
+	if err != nil {
+		ms.log.Error("Invalid UNSUBSCRIBE_URL %s: %v", config.URL, err)
+		return ""
+	}
+
+	query := link.Query()
+	query.Set("token", ms.unsubscribeToken(req.GetTo(), req.GetCategory()))
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+// Unsubscribe => Shows unsubscribe confirmation for GET, suppresses the address for POST
+// POST is also sent by mail clients for one click unsubscribes (RFC 8058), GET never unsubscribes
+// as links are opened by mail scanners too.
+func (ms *MessageService) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	page := struct {
+		Address  string
+		Category string
+		Done     bool
+		Error    string
+	}{}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	address, category, err := ms.parseUnsubscribeToken(r.URL.Query().Get("token"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		page.Error = "This unsubscribe link is not valid."
+		_ = unsubscribePage.Execute(w, page)
+		return
+	}
+	page.Address = address
+	page.Category = category
+
+	if r.Method == http.MethodPost {
+		err := ms.Redis.AddSuppression(r.Context(), &protos.Suppression{
+			Address:   address,
+			Category:  category,
+			Reason:    "unsubscribed",
+			Source:    unsubscribeSource,
+			CreatedAt: ptypes.TimestampNow(),
+		})
+		if err != nil {
+			ms.log.Error("Error occurred while unsubscribing %s from %q: %v", address, category, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			page.Error = "Unable to unsubscribe, please try again later."
+			_ = unsubscribePage.Execute(w, page)
+			return
+		}
+
+		ms.log.Info("Unsubscribed %s from %q", address, category)
+		page.Done = true
+	}
+
+	_ = unsubscribePage.Execute(w, page)
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+)
+
+func newUnsubscribeTestService(secret string) *MessageService {
+	return &MessageService{config: &configs.ServerConfig{
+		Unsubscribe: &configs.UnsubscribeConfig{URL: "https://example.com/unsubscribe", Secret: secret},
+	}}
+}
+
+func TestUnsubscribeTokenRoundTrip(t *testing.T) {
+	ms := newUnsubscribeTestService("secret")
+
+	for _, category := range []string{"marketing", ""} {
+		address, parsedCategory, err := ms.parseUnsubscribeToken(ms.unsubscribeToken("User@Example.com", category))
+		if err != nil || address != "user@example.com" || parsedCategory != category {
+			t.Errorf("parseUnsubscribeToken() = %s, %s, %v, want user@example.com, %q", address, parsedCategory, err, category)
+		}
+	}
+}
+
+func TestUnsubscribeTokenRejected(t *testing.T) {
+	ms := newUnsubscribeTestService("secret")
+	token := ms.unsubscribeToken("user@example.com", "marketing")
+	signature := token[strings.Index(token, ".")+1:]
+	withPayload := func(payload string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + signature
+	}
+
+	tests := []struct {
+		name  string
+		ms    *MessageService
+		token string
+	}{
+		{"tampered address", ms, withPayload("other@example.com\nmarketing")},
+		{"tampered category", ms, withPayload("user@example.com\n")},
+		{"wrong secret", newUnsubscribeTestService("other secret"), token},
+		{"empty secret", newUnsubscribeTestService(""), newUnsubscribeTestService("").unsubscribeToken("user@example.com", "marketing")},
+		{"without signature", ms, token[:strings.Index(token, ".")]},
+		{"invalid encoding", ms, "not base64!." + signature},
+		{"empty", ms, ""},
+	}
+	for _, test := range tests {
+		if address, _, err := test.ms.parseUnsubscribeToken(test.token); err != errInvalidUnsubscribeToken {
+			t.Errorf("%s: parseUnsubscribeToken() = %s, %v, want invalid token", test.name, address, err)
+		}
+	}
+}
+
+func TestMetricsNotOnPublicHandler(t *testing.T) {
+	ms := &MessageService{}
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		code    int
+	}{
+		{"public handler", ms.HTTPHandler(), http.StatusNotFound},
+		{"metrics handler", MetricsHandler(), http.StatusOK},
+	}
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		test.handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		if recorder.Code != test.code {
+			t.Errorf("%s: GET /metrics = %d, want %d", test.name, recorder.Code, test.code)
+		}
+	}
+}
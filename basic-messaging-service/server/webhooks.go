@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/metrics"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications/email"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+const (
+	// maxWebhookBody => largest webhook request accepted, sendgrid batches events in requests
+	maxWebhookBody = 5 << 20
+	// webhookEventTTL => how long received events are remembered, sendgrid retries failed posts for 24 hours
+	webhookEventTTL = 48 * time.Hour
+)
+
+// sendGridStates => delivery state of sendgrid events, other events (eg: processed) are ignored
+var sendGridStates = map[string]protos.DeliveryState{
+	email.EventDelivered:   protos.DeliveryState_DELIVERED,
+	email.EventDeferred:    protos.DeliveryState_DEFERRED,
+	email.EventBounce:      protos.DeliveryState_BOUNCED,
+	email.EventDropped:     protos.DeliveryState_DROPPED,
+	email.EventOpen:        protos.DeliveryState_OPENED,
+	email.EventClick:       protos.DeliveryState_CLICKED,
+	email.EventSpamReport:  protos.DeliveryState_SPAM_REPORTED,
+	email.EventUnsubscribe: protos.DeliveryState_UNSUBSCRIBED,
+}
+
+// SendGridWebhook => Receives sendgrid event webhook and updates delivery status of messages
+// Hard bounces and spam reports suppress the recipient. Failures are answered with 500, so sendgrid retries them.
+func (ms *MessageService) SendGridWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if ms.sendGridKey == nil {
+		http.Error(w, "sendgrid webhook is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "unable to read body", http.StatusBadRequest)
+		return
+	}
+
+	err = email.VerifyWebhookSignature(ms.sendGridKey, r.Header.Get(email.SendGridSignatureHeader),
+		r.Header.Get(email.SendGridTimestampHeader), payload, ms.config.SendGrid.WebhookTolerance)
+	if err != nil {
+		ms.log.Warn("Rejected sendgrid webhook from %s: %v", r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var events []email.WebhookEvent
+	if err := json.Unmarshal(payload, &events); err != nil {
+		http.Error(w, "invalid events", http.StatusBadRequest)
+		return
+	}
+
+	for _, event := range events {
+		if err := ms.handleSendGridEvent(r.Context(), event); err != nil {
+			ms.log.Error("Error occurred while handling sendgrid %s event %s: %v", event.Event, event.EventID, err)
+			http.Error(w, "unable to handle events", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleSendGridEvent => records event in status of its message, events seen before are skipped
+func (ms *MessageService) handleSendGridEvent(ctx context.Context, event email.WebhookEvent) error {
+	state, ok := sendGridStates[event.Event]
+	if !ok {
+		return nil
+	}
+
+	if event.EventID != "" {
+		claimed, err := ms.Redis.ClaimWebhookEvent(ctx, email.SENDGRID, event.EventID, webhookEventTTL)
+		if err != nil || !claimed {
+			return err
+		}
+	}
+
+	err := ms.recordSendGridEvent(ctx, state, event)
+	if err != nil && event.EventID != "" {
+		// let the retried post handle it again
+		if releaseErr := ms.Redis.ReleaseWebhookEvent(ctx, email.SENDGRID, event.EventID); releaseErr != nil {
+			ms.log.Error("Error occurred while releasing sendgrid event %s: %v", event.EventID, releaseErr)
+		}
+	}
+
+	return err
+}
+
+func (ms *MessageService) recordSendGridEvent(ctx context.Context, state protos.DeliveryState, event email.WebhookEvent) error {
+	at, err := ptypes.TimestampProto(time.Unix(event.Timestamp, 0))
+	if err != nil {
+		at = ptypes.TimestampNow()
+	}
+
+	detail := event.Email
+	for _, info := range []string{event.Reason, event.Response, event.URL} {
+		if info != "" {
+			detail += ": " + info
+		}
+	}
+
+	if reason := suppressionReason(event); reason != "" {
+		err := ms.Redis.AddSuppression(ctx, &protos.Suppression{
+			Address:   event.Email,
+			Reason:    reason,
+			Source:    email.SENDGRID,
+			CreatedAt: ptypes.TimestampNow(),
+		})
+		if err != nil {
+			return err
+		}
+		ms.log.Info("Suppressed %s, reason: %s", event.Email, reason)
+	}
+
+	if event.MessageID == "" {
+		return nil
+	}
+
+	err = ms.Redis.UpdateStatus(ctx, event.MessageID, &protos.StatusEvent{
+		State:    state,
+		At:       at,
+		Provider: email.SENDGRID,
+		Detail:   detail,
+	}, ms.config.Status.TTL)
+	if err == redis.Nil {
+		// status expired or message was not sent by us
+		return nil
+	} else if err == nil {
+		metrics.MessageStates.WithLabelValues(protos.NotificationType_EMAIL.String(), state.String()).Inc()
+	}
+
+	return err
+}
+
+// suppressionReason => why recipient of event should not get any more emails, empty when it still can
+func suppressionReason(event email.WebhookEvent) string {
+	switch {
+	case event.Event == email.EventBounce && event.Type == email.BounceTypeBounce:
+		return strings.TrimSpace("hard bounce " + event.Reason)
+	case event.Event == email.EventSpamReport:
+		return "spam report"
+	default:
+		return ""
+	}
+}
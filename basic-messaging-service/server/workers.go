@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/db"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/metrics"
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/notifications"
+	protos "github.com/frost060/go-microservice-basic/basic-messaging-service/protos/notifications"
+)
+
+// Backoff used by workers while redis is unreachable
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = time.Minute
+)
+
+// promoteBatchSize => max messages moved per scheduled set in one redis call
+const promoteBatchSize = 100
+
+// StartPromoter => moves due scheduled messages into their dispatch queues, until ctx is done
+// Safe to run on multiple instances, each message is promoted only once.
+func (ms *MessageService) StartPromoter(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	priorities := []protos.Priority{protos.Priority_HIGH, protos.Priority_NORMAL, protos.Priority_BULK}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, priority := range priorities {
+			for {
+				promoted, err := ms.queue.PromoteDue(ctx, priority, time.Now(), promoteBatchSize)
+				if err != nil {
+					ms.log.Error("Error occurred while promoting scheduled messages: %v", err)
+					break
+				}
+				if promoted > 0 {
+					ms.log.Info("Promoted %d scheduled messages to %s queue", promoted, db.QueueKey(priority))
+				}
+				if promoted < promoteBatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// StartReaper => returns messages with expired leases (worker crashed or got stuck) to their queues,
+// until ctx is done
+func (ms *MessageService) StartReaper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, key := range priorityOrder {
+			requeued, err := ms.queue.ReapExpired(ctx, key, time.Now(), ms.config.Queue.LeaseTimeout)
+			if err != nil {
+				ms.log.Error("Error occurred while reaping expired leases: %v", err)
+				continue
+			}
+			if requeued > 0 {
+				ms.log.Warn("Returned %d messages with expired lease to %s queue", requeued, key)
+			}
+		}
+	}
+}
+
+// Worker => dispatches one message at a time
+// failures is the number of consecutive redis errors, used for reconnect backoff
+type Worker struct {
+	ID       int
+	failures int
+}
+
+type WorkerPool struct {
+	Pool chan Worker
+}
+
+func (ms *MessageService) newWorkerPool(noOfRoutines int) *WorkerPool {
+	workerPool := make(chan Worker, noOfRoutines)
+	for i := 0; i < noOfRoutines; i++ {
+		worker := &Worker{
+			ID: i,
+		}
+		ms.log.Info("Created worker with id %d", i)
+		workerPool <- *worker
+	}
+
+	return &WorkerPool{
+		Pool: workerPool,
+	}
+}
+
+// https://play.golang.org/p/HovNRgp6FxH
+// Messages are reserved under a lease and acked only after dispatch, so a message
+// is never lost if the process dies mid dispatch (see StartReaper).
+// Returns once ctx is done and all the in-flight messages are processed.
+func (ms *MessageService) StartDispatchRedis(ctx context.Context, noOfRoutines int, queue db.Queue) {
+	workerPool := ms.newWorkerPool(noOfRoutines)
+	ms.workers.beat()
+	metrics.Workers.Set(float64(noOfRoutines))
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		var worker Worker
+		select {
+		case <-ctx.Done():
+			ms.log.Info("Stopping dispatch workers...")
+			return
+		case worker = <-workerPool.Pool:
+		}
+		// a worker is only back in the pool once it finished its message
+		ms.workers.beat()
+
+		metrics.BusyWorkers.Inc()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ms.dispatchNext(ctx, &worker, queue)
+			metrics.BusyWorkers.Dec()
+			workerPool.Pool <- worker
+		}()
+	}
+}
+
+// dispatchNext => reserves next message and dispatches it
+// Looks into queues in scheduler order, when all are empty waits on one of them
+// (spread across workers) so new messages are picked up right away.
+func (ms *MessageService) dispatchNext(ctx context.Context, worker *Worker, queue db.Queue) {
+	order := ms.scheduler.Next()
+	leaseFor := ms.config.Queue.LeaseTimeout
+
+	lease, err := queue.ReserveAny(ctx, leaseFor, order...)
+	if err == redis.Nil {
+		key := order[worker.ID%len(order)]
+		lease, err = queue.ReserveBlocking(ctx, key, leaseFor, ms.config.Queue.BlockTimeout)
+	}
+
+	var invalid *db.InvalidMessageError
+	switch {
+	case err == redis.Nil:
+		worker.failures = 0
+		return
+	case err != nil && ctx.Err() != nil:
+		// workers are being stopped, reserve was cancelled
+		return
+	case errors.As(err, &invalid):
+		worker.failures = 0
+		ms.log.Error("Dropped invalid message from queue: %v, payload: %q", err, invalid.Payload)
+		return
+	case err != nil:
+		worker.failures++
+		delay := reconnectDelay(worker.failures)
+		ms.log.Error("Worker %d unable to reach redis, retrying in %v: %v", worker.ID, delay, err)
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+		}
+		return
+	}
+	worker.failures = 0
+
+	// Message is already reserved, finish it even if workers are being stopped
+	dispatchCtx, span := startProcessSpan(lease)
+	defer span.End()
+	if err := ms.limiter.Allow(dispatchCtx, lease.Message); err != nil {
+		ms.deferMessage(dispatchCtx, queue, lease, err)
+		return
+	}
+
+	resp, err := ms.dispatch(dispatchCtx, lease.Message)
+	if _, limited := notifications.IsRateLimited(err); limited {
+		ms.deferMessage(dispatchCtx, queue, lease, err)
+		return
+	}
+	if err != nil || !resp.Success {
+		ms.log.Error("Error occurred while dispatching message: %v", err)
+		ms.retryOrDeadLetter(dispatchCtx, queue, lease, err)
+		return
+	}
+
+	ms.log.Info("Successfully sent message, by worker: %d", worker.ID)
+	if err := queue.Ack(dispatchCtx, lease); err != nil {
+		ms.log.Error("Error occurred while acking message: %v", err)
+	}
+}
+
+// reconnectDelay => doubles on every consecutive failure, capped at maxReconnectDelay
+func reconnectDelay(failures int) time.Duration {
+	delay := minReconnectDelay
+	for i := 1; i < failures && delay < maxReconnectDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxReconnectDelay {
+		delay = maxReconnectDelay
+	}
+
+	return delay
+}
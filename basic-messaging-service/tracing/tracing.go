@@ -0,0 +1,145 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/propagation"
+	"go.opentelemetry.io/otel/api/standard"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/trace/jaeger"
+	"go.opentelemetry.io/otel/exporters/trace/stdout"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/frost060/go-microservice-basic/basic-messaging-service/configs"
+)
+
+// Name => instrumentation name of the spans started by the service
+const Name = "github.com/frost060/go-microservice-basic/basic-messaging-service"
+
+// Exporters
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterJaeger = "jaeger"
+	ExporterOTLP   = "otlp"
+)
+
+const (
+	defaultJaegerEndpoint = "http://localhost:14268/api/traces"
+	defaultOTLPEndpoint   = "localhost:55680"
+)
+
+// Tracer => tracer of the service, spans are not recorded unless Setup registered an exporter
+func Tracer() trace.Tracer {
+	return global.Tracer(Name)
+}
+
+// Setup => registers the global trace provider exporting to configured exporter
+// Returned func flushes pending spans and stops the exporter, it should be called on shutdown.
+func Setup(config *configs.TracingConfig) (func(), error) {
+	var processor sdktrace.SpanProcessor
+	stop := func() {}
+
+	switch config.Exporter {
+	case ExporterNone, "":
+		return stop, nil
+	case ExporterStdout, ExporterFile:
+		options := stdout.Options{}
+		if config.Exporter == ExporterFile {
+			file, err := os.OpenFile(config.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				return nil, err
+			}
+			options.Writer = file
+			stop = func() { _ = file.Close() }
+		}
+		exporter, err := stdout.NewExporter(options)
+		if err != nil {
+			return nil, err
+		}
+		processor = sdktrace.NewSimpleSpanProcessor(exporter)
+	case ExporterJaeger:
+		endpoint := config.Endpoint
+		if endpoint == "" {
+			endpoint = defaultJaegerEndpoint
+		}
+		exporter, err := jaeger.NewRawExporter(jaeger.WithCollectorEndpoint(endpoint),
+			jaeger.WithProcess(jaeger.Process{ServiceName: config.ServiceName}))
+		if err != nil {
+			return nil, err
+		}
+		processor = sdktrace.NewSimpleSpanProcessor(exporter)
+		stop = exporter.Flush
+	case ExporterOTLP:
+		endpoint := config.Endpoint
+		if endpoint == "" {
+			endpoint = defaultOTLPEndpoint
+		}
+		exporter, err := otlp.NewExporter(otlp.WithInsecure(), otlp.WithAddress(endpoint))
+		if err != nil {
+			return nil, err
+		}
+		processor, err = sdktrace.NewBatchSpanProcessor(exporter)
+		if err != nil {
+			return nil, err
+		}
+		stop = func() { _ = exporter.Stop() }
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", config.Exporter)
+	}
+
+	provider, err := sdktrace.NewProvider(
+		sdktrace.WithConfig(sdktrace.Config{
+			DefaultSampler: sdktrace.ParentSample(sdktrace.ProbabilitySampler(config.SampleRatio)),
+		}),
+		sdktrace.WithResource(resource.New(standard.ServiceNameKey.String(config.ServiceName))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	provider.RegisterSpanProcessor(processor)
+	global.SetTraceProvider(provider)
+
+	return func() {
+		// flushes spans of batch processor before exporter is stopped
+		provider.UnregisterSpanProcessor(processor)
+		stop()
+	}, nil
+}
+
+// Inject => trace context of ctx as a carrier which can be stored with a message, nil when there is no trace
+func Inject(ctx context.Context) map[string]string {
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		return nil
+	}
+
+	carrier := supplier{}
+	propagation.InjectHTTP(ctx, global.Propagators(), carrier)
+	return carrier
+}
+
+// Extract => returns ctx with the remote span of carrier, spans started with it continue that trace
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+
+	return propagation.ExtractHTTP(ctx, global.Propagators(), supplier(carrier))
+}
+
+// supplier => map carrier of propagated headers
+type supplier map[string]string
+
+func (s supplier) Get(key string) string {
+	return s[key]
+}
+
+func (s supplier) Set(key, value string) {
+	s[key] = value
+}
@@ -0,0 +1,231 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/frost060/go-microservice-basic/rest-api-mongo/configs"
+	"github.com/frost060/go-microservice-basic/rest-api-mongo/logging"
+)
+
+// Reloader , keeps the client certificate and CAs of the messaging service loaded from files,
+// replacing them when the files change so rotated certificates are used without a restart
+type Reloader struct {
+	config *configs.MessagingConfig
+	log    *logging.LogWrapper
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	roots    *x509.CertPool
+	modTimes []time.Time
+}
+
+// NewReloader , returns a reloader with the certificate files loaded, fails when they are invalid
+func NewReloader(config *configs.MessagingConfig, l *logging.LogWrapper) (*Reloader, error) {
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return nil, errors.New("client certificate and key files must be given together")
+	}
+
+	r := &Reloader{config: config, log: l}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// files , files loaded by the reloader, each of them is optional
+func (r *Reloader) files() []string {
+	var files []string
+	if r.config.CAFile != "" {
+		files = append(files, r.config.CAFile)
+	}
+	if r.config.CertFile != "" {
+		files = append(files, r.config.CertFile, r.config.KeyFile)
+	}
+	return files
+}
+
+// load , reads all the files, nothing is replaced when one of them is invalid
+// Failed files are not read again until they change, eg: once key of a replaced certificate is written.
+func (r *Reloader) load() error {
+	modTimes, err := modTimes(r.files())
+	if err != nil {
+		return err
+	}
+	cert, roots, err := r.read()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.modTimes = modTimes
+	if err != nil {
+		return err
+	}
+	r.cert = cert
+	r.roots = roots
+	return nil
+}
+
+func (r *Reloader) read() (*tls.Certificate, *x509.CertPool, error) {
+	var roots *x509.CertPool
+	if r.config.CAFile != "" {
+		var err error
+		if roots, err = loadPool(r.config.CAFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if r.config.CertFile == "" {
+		return nil, roots, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to load client certificate: %v", err)
+	}
+	return &cert, roots, nil
+}
+
+// changed , reports whether any of the files was modified since it was last loaded
+func (r *Reloader) changed() (bool, error) {
+	modTimes, err := modTimes(r.files())
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[i]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Watch , checks the files for changes every interval until ctx is done
+// A failed reload keeps the current certificates.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := r.changed()
+		if err != nil {
+			r.log.Error("Unable to check certificate files: %v", err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		if err := r.load(); err != nil {
+			r.log.Error("Unable to reload certificates, keeping current ones: %v", err)
+			continue
+		}
+		r.log.Info("Reloaded messaging service certificates")
+	}
+}
+
+// ClientConfig , tls config of the messaging service client, every handshake uses the latest loaded certificates
+func (r *Reloader) ClientConfig() (*tls.Config, error) {
+	serverName := r.config.ServerName
+	if serverName == "" {
+		host, _, err := net.SplitHostPort(r.config.Address)
+		if err != nil {
+			return nil, err
+		}
+		serverName = host
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			if r.cert == nil {
+				// no certificate is sent, service rejects the handshake if it requires one
+				return &tls.Certificate{}, nil
+			}
+			return r.cert, nil
+		},
+		// roots can change after the config is created, so certificate of the service
+		// is verified here instead of by the default verification
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return r.verify(serverName, rawCerts)
+		},
+	}, nil
+}
+
+// verify , verifies certificate chain of the service against the latest loaded roots (system roots when none)
+func (r *Reloader) verify(serverName string, rawCerts [][]byte) error {
+	if len(rawCerts) == 0 {
+		return errors.New("messaging service did not present a certificate")
+	}
+
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs[i] = cert
+	}
+
+	r.mu.RLock()
+	roots := r.roots
+	r.mu.RUnlock()
+
+	options := x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range certs[1:] {
+		options.Intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(options)
+	return err
+}
+
+// loadPool , reads PEM encoded certificates of file into a pool
+func loadPool(file string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("no certificates found in " + file)
+	}
+	return pool, nil
+}
+
+func modTimes(files []string) ([]time.Time, error) {
+	modTimes := make([]time.Time, len(files))
+	for i, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
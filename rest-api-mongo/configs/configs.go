@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -24,11 +26,39 @@ type SendGridConfig struct {
 	ApiKey string
 }
 
+// TracingConfig , where spans are exported. Exporter is none (default), stdout, file, jaeger or otlp.
+// Endpoint is the jaeger collector url or otlp collector address, File is used by the file exporter.
+type TracingConfig struct {
+	Exporter    string
+	Endpoint    string
+	File        string
+	ServiceName string
+	SampleRatio float64
+}
+
+// MessagingConfig , messaging service client. TLS is used when TLSEnabled, CAFile verifies the certificate
+// of the service (system roots when not set) and CertFile, KeyFile are presented as client certificate (mutual TLS).
+// ServerName is expected in the service certificate, host of Address when not set.
+// APIKey identifies this service to the messaging service.
+// Files are checked for changes every ReloadInterval, so rotated certificates are used without a restart.
+type MessagingConfig struct {
+	Address        string
+	APIKey         string
+	TLSEnabled     bool
+	CAFile         string
+	CertFile       string
+	KeyFile        string
+	ServerName     string
+	ReloadInterval time.Duration
+}
+
 type Config struct {
-	Google   *GoogleConfig
-	JWT      *JWTConfig
-	SendGrid *SendGridConfig
-	RootPath string
+	Google    *GoogleConfig
+	JWT       *JWTConfig
+	SendGrid  *SendGridConfig
+	Tracing   *TracingConfig
+	Messaging *MessagingConfig
+	RootPath  string
 }
 
 // NewConfig returns a new Config struct
@@ -37,13 +67,17 @@ func NewConfig() *Config {
 	googleConfig := NewGoogleConfig()
 	jwtConfig := NewJWTConfig()
 	sendGrid := NewSendGridConfig()
+	tracing := NewTracingConfig()
+	messaging := NewMessagingConfig()
 	rootPath, _ := filepath.Abs("./")
 
 	return &Config{
-		Google:   googleConfig,
-		JWT:      jwtConfig,
-		SendGrid: sendGrid,
-		RootPath: rootPath,
+		Google:    googleConfig,
+		JWT:       jwtConfig,
+		SendGrid:  sendGrid,
+		Tracing:   tracing,
+		Messaging: messaging,
+		RootPath:  rootPath,
 	}
 }
 
@@ -87,6 +121,43 @@ func NewSendGridConfig() *SendGridConfig {
 	}
 }
 
+func NewTracingConfig() *TracingConfig {
+	sampleRatio, err := strconv.ParseFloat(getEnv("TRACING_SAMPLE_RATIO", "1"), 64)
+	if err != nil {
+		sampleRatio = 1
+	}
+
+	return &TracingConfig{
+		Exporter:    strings.ToLower(getEnv("TRACING_EXPORTER", "none")),
+		Endpoint:    getEnv("TRACING_ENDPOINT", ""),
+		File:        getEnv("TRACING_FILE", "traces.json"),
+		ServiceName: getEnv("TRACING_SERVICE_NAME", "rest-api-mongo"),
+		SampleRatio: sampleRatio,
+	}
+}
+
+func NewMessagingConfig() *MessagingConfig {
+	reloadInterval, err := time.ParseDuration(getEnv("MESSAGING_TLS_RELOAD_INTERVAL", "1m"))
+	if err != nil {
+		reloadInterval = time.Minute
+	}
+
+	config := &MessagingConfig{
+		Address:        getEnv("MESSAGING_SERVICE_ADDRESS", "localhost:9092"),
+		APIKey:         getEnv("MESSAGING_API_KEY", ""),
+		CAFile:         getEnv("MESSAGING_TLS_CA_FILE", ""),
+		CertFile:       getEnv("MESSAGING_TLS_CERT_FILE", ""),
+		KeyFile:        getEnv("MESSAGING_TLS_KEY_FILE", ""),
+		ServerName:     getEnv("MESSAGING_TLS_SERVER_NAME", ""),
+		ReloadInterval: reloadInterval,
+	}
+	// TLS is implied by any of its files
+	config.TLSEnabled = strings.ToLower(getEnv("MESSAGING_TLS", "false")) == "true" ||
+		config.CAFile != "" || config.CertFile != ""
+
+	return config
+}
+
 // Simple helper function to read an environment or return a default value
 func getEnv(key string, defaultVal string) string {
 	if value, exists := os.LookupEnv(key); exists {
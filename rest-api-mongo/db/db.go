@@ -12,7 +12,7 @@ import (
 
 func ConnectDB(log *logging.LogWrapper) *mongo.Database {
 	log.Info("Connecting to mongodb...")
-	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
+	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017").SetMonitor(newCommandMonitor())
 	client, err := mongo.NewClient(clientOptions)
 
 	if err != nil {
@@ -24,14 +24,14 @@ func NewTodoRepo(db *mongo.Database, l *logging.LogWrapper) *TodoRepo {
 }
 
 // GetAll returns all the todo from db
-func (t *TodoRepo) GetAll() (*[]models.Todo, error) {
+func (t *TodoRepo) GetAll(ctx context.Context) (*[]models.Todo, error) {
 	var todos []models.Todo
-	cursor, err := t.Instance.Find(context.TODO(), bson.M{})
+	cursor, err := t.Instance.Find(ctx, bson.M{})
 	if err != nil {
 		return nil, err
 	}
 
-	for cursor.Next(context.TODO()) {
+	for cursor.Next(ctx) {
 		var todo models.Todo
 		err = cursor.Decode(&todo)
 		if err != nil {
@@ -45,10 +45,10 @@ func (t *TodoRepo) GetAll() (*[]models.Todo, error) {
 }
 
 // FindByID returns a todo from db given todo id
-func (t *TodoRepo) Get(id int64) (*models.Todo, error) {
+func (t *TodoRepo) Get(ctx context.Context, id int64) (*models.Todo, error) {
 	var todo models.Todo
 
-	err := t.Instance.FindOne(context.TODO(),
+	err := t.Instance.FindOne(ctx,
 		bson.M{"_id": id}).Decode(&todo)
 	if err != nil {
 		t.log.Error("Error occurred while querying DB", "error", err)
@@ -59,9 +59,9 @@ func (t *TodoRepo) Get(id int64) (*models.Todo, error) {
 }
 
 // Save saves a todo to the db
-func (t *TodoRepo) Save(todo *models.Todo) error {
+func (t *TodoRepo) Save(ctx context.Context, todo *models.Todo) error {
 	todo.ID = utils.GenerateID()
-	insertResult, err := t.Instance.InsertOne(context.TODO(), &todo)
+	insertResult, err := t.Instance.InsertOne(ctx, &todo)
 	if err != nil {
 		t.log.Error("Error while saving document to todo db", "error", err)
 		return err
@@ -72,7 +72,7 @@ func (t *TodoRepo) Save(todo *models.Todo) error {
 }
 
 // Delete deletes a todo from db given a todo id
-func (t *TodoRepo) Delete(id int64) error {
-	_, err := t.Instance.DeleteOne(context.TODO(), bson.M{"_id": id})
+func (t *TodoRepo) Delete(ctx context.Context, id int64) error {
+	_, err := t.Instance.DeleteOne(ctx, bson.M{"_id": id})
 	return err
 }
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/trace"
+	"google.golang.org/grpc/codes"
+
+	"github.com/frost060/go-microservice-basic/rest-api-mongo/tracing"
+)
+
+// commandTracer , starts a span for every mongo command run within a traced request
+// Command documents are not recorded as they have user data.
+type commandTracer struct {
+	mu    sync.Mutex
+	spans map[int64]trace.Span
+}
+
+// newCommandMonitor returns a mongo command monitor which traces commands
+func newCommandMonitor() *event.CommandMonitor {
+	ct := &commandTracer{spans: make(map[int64]trace.Span)}
+	return &event.CommandMonitor{
+		Started:   ct.started,
+		Succeeded: ct.succeeded,
+		Failed:    ct.failed,
+	}
+}
+
+func (ct *commandTracer) started(ctx context.Context, evt *event.CommandStartedEvent) {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return
+	}
+
+	_, span := tracing.Tracer().Start(ctx, "mongo."+evt.CommandName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			kv.String("db.system", "mongodb"),
+			kv.String("db.name", evt.DatabaseName),
+			kv.String("db.operation", evt.CommandName),
+		))
+
+	ct.mu.Lock()
+	ct.spans[evt.RequestID] = span
+	ct.mu.Unlock()
+}
+
+func (ct *commandTracer) succeeded(_ context.Context, evt *event.CommandSucceededEvent) {
+	if span := ct.pop(evt.RequestID); span != nil {
+		span.End()
+	}
+}
+
+func (ct *commandTracer) failed(_ context.Context, evt *event.CommandFailedEvent) {
+	if span := ct.pop(evt.RequestID); span != nil {
+		span.SetStatus(codes.Unknown, evt.Failure)
+		span.End()
+	}
+}
+
+func (ct *commandTracer) pop(requestID int64) trace.Span {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	span := ct.spans[requestID]
+	delete(ct.spans, requestID)
+	return span
+}
@@ -25,10 +25,10 @@ func NewUserRepo(db *mongo.Database, l *logging.LogWrapper) *UserRepo {
 }
 
 // FindByID returns a user from db given user id
-func (u *UserRepo) FindByID(id int64) (*models.User, error) {
+func (u *UserRepo) FindByID(ctx context.Context, id int64) (*models.User, error) {
 	var user models.User
 
-	err := u.Instance.FindOne(context.TODO(),
+	err := u.Instance.FindOne(ctx,
 		bson.M{models.ID: id}).Decode(&user)
 	if err != nil {
 		u.log.Error("FindByID, error occurred while querying DB", "error", err)
@@ -39,10 +39,10 @@ func (u *UserRepo) FindByID(id int64) (*models.User, error) {
 }
 
 // FindByUsername returns a user from db given username
-func (u *UserRepo) FindByUsername(username string) (*models.User, error) {
+func (u *UserRepo) FindByUsername(ctx context.Context, username string) (*models.User, error) {
 	var user models.User
 
-	err := u.Instance.FindOne(context.TODO(),
+	err := u.Instance.FindOne(ctx,
 		bson.M{models.Username: username}).Decode(&user)
 	if err != nil {
 		u.log.Error("FindByUsername, error occurred while querying DB", "error", err)
@@ -53,12 +53,12 @@ func (u *UserRepo) FindByUsername(username string) (*models.User, error) {
 }
 
 // Save saves a user to the db
-func (u *UserRepo) Save(user *models.User) error {
+func (u *UserRepo) Save(ctx context.Context, user *models.User) error {
 	user.ID = utils.GenerateID()
 	opts := options.Update().SetUpsert(true)
 	findQuery := bson.M{models.Username: user.Username}
 
-	updateResult, err := u.Instance.UpdateOne(context.TODO(), findQuery, &user, opts)
+	updateResult, err := u.Instance.UpdateOne(ctx, findQuery, &user, opts)
 	if err != nil {
 		u.log.Error("Error while saving document to users db", "error", err)
 		return err
@@ -72,11 +72,11 @@ func (u *UserRepo) Save(user *models.User) error {
 // Password expires in 1 day and can be used only once.
 // Note that we are not returning error here if we dont find the user for security reasons.
 // Always send status OK, saying will send an email if email belongs to an user.
-func (u *UserRepo) ResetPassword(email string) (*utils.TagClaim, string, error) {
+func (u *UserRepo) ResetPassword(ctx context.Context, email string) (*utils.TagClaim, string, error) {
 	var user models.User
 	findQuery := bson.M{models.Username: email}
 
-	err := u.Instance.FindOne(context.TODO(), findQuery).Decode(&user)
+	err := u.Instance.FindOne(ctx, findQuery).Decode(&user)
 	if err != nil {
 		u.log.Error("RestPassword, error occurred while querying DB", "error", err)
 		return nil, "", nil
@@ -92,7 +92,7 @@ func (u *UserRepo) ResetPassword(email string) (*utils.TagClaim, string, error)
 	updateQuery := bson.D{{
 		"$set", bson.M{models.ResetPassword: uuidToken.String()},
 	}}
-	_, err = u.Instance.UpdateOne(context.TODO(), findQuery, updateQuery, opts)
+	_, err = u.Instance.UpdateOne(ctx, findQuery, updateQuery, opts)
 	if err != nil {
 		u.log.Error("ResetPassword, error occurred while setting reset password uuid token", "error", err)
 		return nil, "", err
@@ -103,7 +103,7 @@ func (u *UserRepo) ResetPassword(email string) (*utils.TagClaim, string, error)
 
 // ValidateResetPassword validates claim in the url by checking if Identity and uuid combination exists in the user db.
 // If it exists then we just remove the saved uuid (As tag is one time use only).
-func (u *UserRepo) ValidateResetPassword(claim *utils.TagClaim) (bool, error) {
+func (u *UserRepo) ValidateResetPassword(ctx context.Context, claim *utils.TagClaim) (bool, error) {
 	var user models.User
 	findQuery := bson.M{models.Username: claim.Identity, models.ResetPassword: claim.ID}
 	opts := options.FindOneAndUpdate().SetUpsert(false).SetReturnDocument(options.After)
@@ -111,7 +111,7 @@ func (u *UserRepo) ValidateResetPassword(claim *utils.TagClaim) (bool, error) {
 		"$unset", bson.M{models.ResetPassword: 1},
 	}}
 
-	err := u.Instance.FindOneAndUpdate(context.TODO(), findQuery, updateQuery, opts).Decode(&user)
+	err := u.Instance.FindOneAndUpdate(ctx, findQuery, updateQuery, opts).Decode(&user)
 	if err != nil {
 		return false, err
 	}
@@ -119,7 +119,7 @@ func (u *UserRepo) ValidateResetPassword(claim *utils.TagClaim) (bool, error) {
 	return user.ResetPasswordUUID == "", nil
 }
 
-func (u *UserRepo) VerifyUser(userID int64, email string) (*utils.TagClaim, string, error) {
+func (u *UserRepo) VerifyUser(ctx context.Context, userID int64, email string) (*utils.TagClaim, string, error) {
 	var user models.User
 
 	findQuery := bson.M{models.ID: userID}
@@ -139,7 +139,7 @@ func (u *UserRepo) VerifyUser(userID int64, email string) (*utils.TagClaim, stri
 		"$set", bson.M{models.VerifyUser: uuidToken.String()},
 	}}
 
-	err = u.Instance.FindOneAndUpdate(context.TODO(), findQuery, updateQuery, opts).Decode(&user)
+	err = u.Instance.FindOneAndUpdate(ctx, findQuery, updateQuery, opts).Decode(&user)
 	if err != nil {
 		return nil, "", err
 	}
@@ -148,7 +148,7 @@ func (u *UserRepo) VerifyUser(userID int64, email string) (*utils.TagClaim, stri
 }
 
 // ValidateVerifyUser validates the verify link user clicks on and sets verified flag in db.
-func (u *UserRepo) ValidateVerifyUser(claim *utils.TagClaim) (bool, error) {
+func (u *UserRepo) ValidateVerifyUser(ctx context.Context, claim *utils.TagClaim) (bool, error) {
 	var user models.User
 	findQuery := bson.M{models.Username: claim.Identity, models.VerifyUser: claim.ID}
 	opts := options.FindOneAndUpdate().SetUpsert(false).SetReturnDocument(options.After)
@@ -158,7 +158,7 @@ func (u *UserRepo) ValidateVerifyUser(claim *utils.TagClaim) (bool, error) {
 		"$set", bson.M{models.Verified: 1},
 	}}
 
-	err := u.Instance.FindOneAndUpdate(context.TODO(), findQuery, updateQuery, opts).Decode(&user)
+	err := u.Instance.FindOneAndUpdate(ctx, findQuery, updateQuery, opts).Decode(&user)
 	if err != nil {
 		return false, err
 	}
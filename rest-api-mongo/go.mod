@@ -18,9 +18,15 @@ require (
 	github.com/sendgrid/sendgrid-go v3.6.0+incompatible
 	github.com/sirupsen/logrus v1.6.0
 	go.mongodb.org/mongo-driver v1.3.4
+	go.opentelemetry.io/contrib/instrumentation/gorilla/mux v0.7.0
+	go.opentelemetry.io/otel v0.7.0
+	go.opentelemetry.io/otel/exporters/otlp v0.7.0
+	go.opentelemetry.io/otel/exporters/trace/jaeger v0.7.0
 	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
 	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
 	golang.org/x/sync v0.0.0-20200625203802-6e8e738ad208 // indirect
 	google.golang.org/grpc v1.30.0
 	gopkg.in/go-playground/assert.v1 v1.2.1 // indirect
 )
+
+replace github.com/frost060/go-microservice-basic/basic-messaging-service => ../basic-messaging-service
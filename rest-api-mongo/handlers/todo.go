@@ -27,7 +27,7 @@ func NewTodoHandler(todoRepo models.TodoRepository, l *logging.LogWrapper, mss p
 
 // GetAllTodos returns all the todos
 func (t *TodoHandler) GetAllTodos(rw http.ResponseWriter, r *http.Request) {
-	rs, err := t.todoRepo.GetAll()
+	rs, err := t.todoRepo.GetAll(r.Context())
 	if err != nil {
 		http.Error(rw, "Failed to load database items", http.StatusInternalServerError)
 		return
@@ -52,7 +52,7 @@ func (t *TodoHandler) GetTodo(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	todo, err := t.todoRepo.Get(todoID)
+	todo, err := t.todoRepo.Get(r.Context(), todoID)
 	if err != nil {
 		http.Error(rw, "Failed to read database", http.StatusInternalServerError)
 		return
@@ -73,7 +73,7 @@ func (t *TodoHandler) AddTodo(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = t.todoRepo.Save(&todo)
+	err = t.todoRepo.Save(r.Context(), &todo)
 	if err != nil {
 		http.Error(rw, "Error occurred while saving todo to DB", http.StatusInternalServerError)
 	}
@@ -91,7 +91,7 @@ func (t *TodoHandler) DeleteTodo(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = t.todoRepo.Delete(todoID)
+	err = t.todoRepo.Delete(r.Context(), todoID)
 	if err != nil {
 		http.Error(
 			rw,
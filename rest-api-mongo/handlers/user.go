@@ -34,7 +34,7 @@ func (u *UserHandler) GetUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := u.userRepo.FindByID(jwtClaim.UserID)
+	user, err := u.userRepo.FindByID(r.Context(), jwtClaim.UserID)
 	if err != nil {
 		http.Error(rw, "Failed to read database", http.StatusInternalServerError)
 		return
@@ -74,7 +74,7 @@ func (u *UserHandler) PerformLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userDB, err := u.userRepo.FindByUsername(userRequest.Username)
+	userDB, err := u.userRepo.FindByUsername(r.Context(), userRequest.Username)
 	if err != nil {
 		http.Error(rw, "Invalid username/password", http.StatusForbidden)
 		return
@@ -125,7 +125,7 @@ func (u *UserHandler) SaveUser(rw http.ResponseWriter, r *http.Request) {
 	}
 	user.Password = hashedPassword
 
-	err = u.userRepo.Save(&user)
+	err = u.userRepo.Save(r.Context(), &user)
 	if err != nil {
 		http.Error(rw, "Error occurred while saving user to DB", http.StatusInternalServerError)
 	}
@@ -152,7 +152,7 @@ func (u *UserHandler) RequestResetPassword(rw http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	tagClaim, name, err := u.userRepo.ResetPassword(username)
+	tagClaim, name, err := u.userRepo.ResetPassword(r.Context(), username)
 	if err != nil || tagClaim == nil {
 		rw.WriteHeader(http.StatusOK)
 		return
@@ -164,14 +164,11 @@ func (u *UserHandler) RequestResetPassword(rw http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	templatePath := u.serverConfig.RootPath + "/web/templates/forgot_password.html"
-	html := utils.GenerateResetPasswordMail(username, name, resetTag, "http://"+r.Host, templatePath)
-
 	message := &protos.MessageRequest{
-		Type:    protos.NotificationType_EMAIL,
-		Msg:     html,
-		Subject: "Reset Password from TEST Service",
-		To:      username,
+		Type:       protos.NotificationType_EMAIL,
+		To:         username,
+		TemplateId: utils.ResetPasswordTemplate,
+		Variables:  utils.ResetPasswordVariables(username, name, resetTag, "http://"+r.Host),
 	}
 	resp, err := u.messagingService.SendNotification(r.Context(), message)
 
@@ -196,7 +193,7 @@ func (u *UserHandler) ResetPassword(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ok, err = u.userRepo.ValidateResetPassword(&tagClaim)
+	ok, err = u.userRepo.ValidateResetPassword(r.Context(), &tagClaim)
 	if err != nil || !ok {
 		http.Error(rw, "Invalid token", http.StatusBadRequest)
 		return
@@ -213,7 +210,7 @@ func (u *UserHandler) RequestVerifyEmail(rw http.ResponseWriter, r *http.Request
 		return
 	}
 
-	tagClaim, name, err := u.userRepo.VerifyUser(jwtClaim.UserID, "")
+	tagClaim, name, err := u.userRepo.VerifyUser(r.Context(), jwtClaim.UserID, "")
 	if err != nil {
 		http.Error(rw, "Failed to read database", http.StatusInternalServerError)
 		return
@@ -225,14 +222,11 @@ func (u *UserHandler) RequestVerifyEmail(rw http.ResponseWriter, r *http.Request
 		return
 	}
 
-	templatePath := u.serverConfig.RootPath + "/web/templates/verify_email.html"
-	html := utils.GenerateVerifyEmailMail(tagClaim.Identity, name, resetTag, "http://"+r.Host, templatePath)
-
 	message := &protos.MessageRequest{
-		Type:    protos.NotificationType_EMAIL,
-		Msg:     html,
-		Subject: "Verify Password from TEST Service",
-		To:      tagClaim.Identity,
+		Type:       protos.NotificationType_EMAIL,
+		To:         tagClaim.Identity,
+		TemplateId: utils.VerifyEmailTemplate,
+		Variables:  utils.VerifyEmailVariables(tagClaim.Identity, name, resetTag, "http://"+r.Host),
 	}
 
 	resp, err := u.messagingService.SendNotification(r.Context(), message)
@@ -258,7 +252,7 @@ func (u *UserHandler) VerifyEmail(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ok, err = u.userRepo.ValidateVerifyUser(&tagClaim)
+	ok, err = u.userRepo.ValidateVerifyUser(r.Context(), &tagClaim)
 	if err != nil || !ok {
 		http.Error(rw, "Invalid token", http.StatusBadRequest)
 		return
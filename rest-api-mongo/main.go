@@ -7,11 +7,16 @@ import (
 	"os/signal"
 	"time"
 
+	"go.opentelemetry.io/otel/instrumentation/grpctrace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
+	"github.com/frost060/go-microservice-basic/rest-api-mongo/certs"
 	"github.com/frost060/go-microservice-basic/rest-api-mongo/configs"
 	"github.com/frost060/go-microservice-basic/rest-api-mongo/logging"
 	"github.com/frost060/go-microservice-basic/rest-api-mongo/routes"
+	"github.com/frost060/go-microservice-basic/rest-api-mongo/services/messaging"
+	"github.com/frost060/go-microservice-basic/rest-api-mongo/tracing"
 	"github.com/joho/godotenv"
 
 	"github.com/frost060/go-microservice-basic/rest-api-mongo/db"
@@ -35,8 +40,51 @@ func main() {
 	serverConfigs := configs.NewConfig()
 	log.Info("Successfully loaded configs")
 
-	// Message Service Client
-	conn, err := grpc.Dial("localhost:9092", grpc.WithInsecure())
+	stopTracing, err := tracing.Setup(serverConfigs.Tracing)
+	if err != nil {
+		log.Error("Unable to setup tracing: %v", err)
+		os.Exit(1)
+	}
+	defer stopTracing()
+
+	// Message Service Client, trace context of requests is sent along
+	tracer := tracing.Tracer()
+	dialOptions := []grpc.DialOption{
+		grpc.WithUnaryInterceptor(grpctrace.UnaryClientInterceptor(tracer)),
+		grpc.WithStreamInterceptor(grpctrace.StreamClientInterceptor(tracer)),
+	}
+
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	if serverConfigs.Messaging.TLSEnabled {
+		reloader, err := certs.NewReloader(serverConfigs.Messaging, log)
+		if err != nil {
+			log.Error("Unable to load messaging service certificates: %v", err)
+			os.Exit(1)
+		}
+		tlsConfig, err := reloader.ClientConfig()
+		if err != nil {
+			log.Error("Invalid messaging service address: %v", err)
+			os.Exit(1)
+		}
+		go reloader.Watch(watchCtx, serverConfigs.Messaging.ReloadInterval)
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		log.Warn("Messaging service TLS is not enabled, connecting without TLS")
+		dialOptions = append(dialOptions, grpc.WithInsecure())
+	}
+
+	if serverConfigs.Messaging.APIKey != "" {
+		if !serverConfigs.Messaging.TLSEnabled {
+			log.Warn("Messaging service api key is sent without TLS")
+		}
+		apiKey := messaging.NewAPIKey(serverConfigs.Messaging.APIKey, serverConfigs.Messaging.TLSEnabled)
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(apiKey))
+	} else {
+		log.Warn("MESSAGING_API_KEY is not set, calls to messaging service are not authenticated")
+	}
+
+	conn, err := grpc.Dial(serverConfigs.Messaging.Address, dialOptions...)
 	if err != nil {
 		log.Error("Error while connecting to messaging service")
 		os.Exit(1)
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 )
@@ -26,8 +27,8 @@ func (t *Todo) ToJSON(w io.Writer) error {
 
 // TodoRepository interface => All available methods for TodoCollection
 type TodoRepository interface {
-	GetAll() (*[]Todo, error)
-	Get(ID int64) (*Todo, error)
-	Save(todo *Todo) error
-	Delete(ID int64) error
+	GetAll(ctx context.Context) (*[]Todo, error)
+	Get(ctx context.Context, ID int64) (*Todo, error)
+	Save(ctx context.Context, todo *Todo) error
+	Delete(ctx context.Context, ID int64) error
 }
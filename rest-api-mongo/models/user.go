@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 
@@ -51,11 +52,11 @@ func (u *User) Validate() error {
 
 // UserRepository interface => All available methods for UserCollection
 type UserRepository interface {
-	FindByID(ID int64) (*User, error)
-	FindByUsername(Username string) (*User, error)
-	Save(user *User) error
-	ResetPassword(email string) (*utils.TagClaim, string, error)
-	ValidateResetPassword(claim *utils.TagClaim) (bool, error)
-	VerifyUser(userID int64, email string) (*utils.TagClaim, string, error)
-	ValidateVerifyUser(claim *utils.TagClaim) (bool, error)
+	FindByID(ctx context.Context, ID int64) (*User, error)
+	FindByUsername(ctx context.Context, Username string) (*User, error)
+	Save(ctx context.Context, user *User) error
+	ResetPassword(ctx context.Context, email string) (*utils.TagClaim, string, error)
+	ValidateResetPassword(ctx context.Context, claim *utils.TagClaim) (bool, error)
+	VerifyUser(ctx context.Context, userID int64, email string) (*utils.TagClaim, string, error)
+	ValidateVerifyUser(ctx context.Context, claim *utils.TagClaim) (bool, error)
 }
@@ -12,12 +12,15 @@ import (
 	"github.com/frost060/go-microservice-basic/rest-api-mongo/logging"
 	"github.com/frost060/go-microservice-basic/rest-api-mongo/middlewares"
 	"github.com/gorilla/mux"
+	muxtrace "go.opentelemetry.io/contrib/instrumentation/gorilla/mux"
 )
 
 // SetupRoutes , sets up all the routes and returns router instance
 func SetupRoutes(
 	repos *db.Repositories, serverConfigs *configs.Config, mss protos.NotificationClient, log *logging.LogWrapper) *mux.Router {
 	router := mux.NewRouter()
+	// traces every request, handlers pass request context on to mongo and messaging service
+	router.Use(muxtrace.Middleware(serverConfigs.Tracing.ServiceName))
 
 	// Handlers
 	todoHandler := handlers.NewTodoHandler(repos.TodoRepo, log, mss)
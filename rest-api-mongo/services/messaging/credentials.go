@@ -0,0 +1,29 @@
+package messaging
+
+import (
+	"context"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// APIKeyMetadataKey , metadata key the messaging service reads api keys from
+const APIKeyMetadataKey = "x-api-key"
+
+// apiKey , sends api key of this service with every call to the messaging service
+type apiKey struct {
+	key        string
+	requireTLS bool
+}
+
+// NewAPIKey , returns per rpc credentials of key, calls are refused without TLS when requireTLS is set
+func NewAPIKey(key string, requireTLS bool) credentials.PerRPCCredentials {
+	return &apiKey{key: key, requireTLS: requireTLS}
+}
+
+func (k *apiKey) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{APIKeyMetadataKey: k.key}, nil
+}
+
+func (k *apiKey) RequireTransportSecurity() bool {
+	return k.requireTLS
+}
@@ -1,20 +1,11 @@
 package utils
 
-import (
-	"io/ioutil"
-	"path/filepath"
-	"strings"
+// Template ids of the mails sent through the messaging service, templates are stored by the messaging service
+const (
+	ResetPasswordTemplate = "forgot_password"
+	VerifyEmailTemplate   = "verify_email"
 )
 
-func FinalizeHTMl(html string, replaceMap map[string]string) string {
-	resultHtml := html
-	for k, v := range replaceMap {
-		resultHtml = strings.ReplaceAll(resultHtml, k, v)
-	}
-
-	return resultHtml
-}
-
 func GenerateUrl(host, tag, path string) string {
 	if path == "" {
 		return host + "/" + tag
@@ -22,41 +13,20 @@ func GenerateUrl(host, tag, path string) string {
 	return host + "/" + path + "/" + tag
 }
 
-func GenerateResetPasswordMail(identity, name, tag, host, templatePath string) string {
-	replaceMap := make(map[string]string)
-	link := GenerateUrl(host, tag, "resetpassword")
-	replaceMap["@@LINK"] = link
-	replaceMap["@@USER"] = identity
-	replaceMap["@@NAME"] = name
-
-	html := GetTemplateFromFile(templatePath)
-	finalHtml := FinalizeHTMl(html, replaceMap)
-
-	return finalHtml
-}
-
-func GenerateVerifyEmailMail(email, name, tag, host, templatePath string) string {
-	replaceMap := make(map[string]string)
-	link := GenerateUrl(host, tag, "verify")
-	replaceMap["@@EMAIL"] = email
-	replaceMap["@@NAME"] = name
-	replaceMap["@@LINK"] = link
-
-	html := GetTemplateFromFile(templatePath)
-	finalHtml := FinalizeHTMl(html, replaceMap)
-
-	return finalHtml
-}
-
-func GetTemplateFromFile(path string) string {
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return ""
+// ResetPasswordVariables , variables of the reset password template
+func ResetPasswordVariables(identity, name, tag, host string) map[string]string {
+	return map[string]string{
+		"link": GenerateUrl(host, tag, "resetpassword"),
+		"user": identity,
+		"name": name,
 	}
+}
 
-	data, err := ioutil.ReadFile(absPath)
-	if err != nil {
-		return ""
+// VerifyEmailVariables , variables of the verify email template
+func VerifyEmailVariables(email, name, tag, host string) map[string]string {
+	return map[string]string{
+		"link":  GenerateUrl(host, tag, "verify"),
+		"email": email,
+		"name":  name,
 	}
-	return string(data)
 }